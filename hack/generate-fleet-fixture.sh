@@ -0,0 +1,138 @@
+#!/usr/bin/env bash
+
+# Copyright 2026 The Telemetry Authors.
+#
+# Licensed under the Apache License, Version 2.0 (the "License");
+# you may not use this file except in compliance with the License.
+# You may obtain a copy of the License at
+#
+#     http://www.apache.org/licenses/LICENSE-2.0
+#
+# Unless required by applicable law or agreed to in writing, software
+# distributed under the License is distributed on an "AS IS" BASIS,
+# WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+# See the License for the specific language governing permissions and
+# limitations under the License.
+
+# Generates a large, fake KKP fleet to verify that the memory usage of the
+# kubermatic agent stays bounded against a real API server; BenchmarkCollect
+# in pkg/agent/kubermatic/v2 measures the same without a cluster:
+#
+#   go test -run - -bench Collect -benchmem ./pkg/agent/kubermatic/v2/
+#
+# The manifests are written to stdout and require the KKP CRDs to be
+# installed; the Seed points to the same cluster, which must be reachable via
+# the seed-kubeconfig Secret:
+#
+#   kind create cluster
+#   kubectl apply -f <kkp>/charts/kubermatic-operator/crd/k8c.io/
+#   kubectl create namespace kubermatic
+#   kubectl -n kubermatic create secret generic seed-kubeconfig \
+#     --from-file=kubeconfig=<(kind get kubeconfig --internal)
+#   USERS=50000 CLUSTERS=10000 hack/generate-fleet-fixture.sh | kubectl apply --server-side -f -
+#
+# Then run the agent and compare the maximum resident set size for different
+# --page-size values, e.g.:
+#
+#   make kubermatic-agent
+#   /usr/bin/time -v _build/kubermatic-agent --record-dir /tmp/records --page-size 500
+
+set -euo pipefail
+
+USERS=${USERS:-50000}
+PROJECTS=${PROJECTS:-$((USERS / 5))}
+CLUSTERS=${CLUSTERS:-10000}
+SSH_KEYS=${SSH_KEYS:-$USERS}
+
+cat << EOF
+apiVersion: kubermatic.k8c.io/v1
+kind: Seed
+metadata:
+  name: fixture
+  namespace: kubermatic
+spec:
+  country: DE
+  location: Hamburg
+  kubeconfig:
+    name: seed-kubeconfig
+    namespace: kubermatic
+  datacenters:
+    byo:
+      country: DE
+      location: Hamburg
+      spec:
+        bringyourown: {}
+EOF
+
+for i in $(seq 1 "$USERS"); do
+  cat << EOF
+---
+apiVersion: kubermatic.k8c.io/v1
+kind: User
+metadata:
+  name: user-$i
+spec:
+  admin: false
+  email: user-$i@example.com
+  name: User $i
+EOF
+done
+
+for i in $(seq 1 "$PROJECTS"); do
+  cat << EOF
+---
+apiVersion: kubermatic.k8c.io/v1
+kind: Project
+metadata:
+  name: project-$i
+spec:
+  name: Project $i
+EOF
+done
+
+for i in $(seq 1 "$SSH_KEYS"); do
+  cat << EOF
+---
+apiVersion: kubermatic.k8c.io/v1
+kind: UserSSHKey
+metadata:
+  name: key-$i
+spec:
+  name: Key $i
+  owner: user-$i@example.com
+  project: project-$(((i - 1) % PROJECTS + 1))
+  clusters:
+  - cluster-$(((i - 1) % CLUSTERS + 1))
+  fingerprint: "00:00:00:00:00:00:00:00:00:00:00:00:00:00:00:00"
+  publicKey: ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIPLACEHOLDERPLACEHOLDERPLACEHOLDERPLACEH user-$i
+EOF
+done
+
+for i in $(seq 1 "$CLUSTERS"); do
+  cat << EOF
+---
+apiVersion: kubermatic.k8c.io/v1
+kind: Cluster
+metadata:
+  name: cluster-$i
+  labels:
+    project-id: project-$(((i - 1) % PROJECTS + 1))
+spec:
+  humanReadableName: Cluster $i
+  version: 1.27.3
+  exposeStrategy: NodePort
+  cloud:
+    dc: byo
+    providerName: bringyourown
+    bringyourown: {}
+  clusterNetwork:
+    dnsDomain: cluster.local
+    proxyMode: ipvs
+    pods:
+      cidrBlocks:
+      - 172.25.0.0/16
+    services:
+      cidrBlocks:
+      - 10.240.16.0/20
+EOF
+done
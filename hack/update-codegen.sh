@@ -28,5 +28,5 @@ go run sigs.k8s.io/controller-tools/cmd/controller-gen \
 
 go run sigs.k8s.io/controller-tools/cmd/controller-gen \
   rbac:roleName=kubernetes-agent-role \
-  paths="./pkg/agent/kubernetes/..." \
+  paths="{./pkg/agent/kubernetes/...,./pkg/consent/...}" \
   output:stdout >> config/rbac.yaml
@@ -26,6 +26,10 @@ type Agent interface {
 	Collect(ctx context.Context) error
 }
 
+// HashOf returns the hex encoded MD5 hash of the string.
+//
+// Deprecated: the hash is unsalted and can be reversed for guessable
+// input, use an Anonymizer instead.
 func HashOf(str string) (string, error) {
 	hasher := md5.New()
 	_, err := hasher.Write([]byte(str))
@@ -0,0 +1,112 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"crypto/hmac"
+	"crypto/md5"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"os"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// AnonymizationSaltEnv is the environment variable holding the secret,
+// per-installation salt used to anonymize identifiers.
+const AnonymizationSaltEnv = "ANONYMIZATION_SALT"
+
+// Anonymizer derives identifiers from names, so that objects can be told
+// apart and related to each other without reporting their names. The same
+// name always results in the same identifier.
+type Anonymizer interface {
+	// ID returns a hex encoded identifier for the value.
+	ID(value string) string
+	// UUID returns an identifier for the value in UUID format.
+	UUID(value string) string
+}
+
+type hmacAnonymizer struct {
+	salt []byte
+}
+
+// NewAnonymizer returns an Anonymizer based on HMAC-SHA256 keyed with the
+// salt. Without knowing the salt identifiers can neither be reversed with
+// a dictionary of common names, nor do equal names collide across
+// installations.
+func NewAnonymizer(salt []byte) Anonymizer {
+	return hmacAnonymizer{salt: salt}
+}
+
+func (a hmacAnonymizer) ID(value string) string {
+	return hex.EncodeToString(a.sum(value))
+}
+
+func (a hmacAnonymizer) UUID(value string) string {
+	var id uuid.UUID
+	copy(id[:], a.sum(value))
+
+	// mark it as a custom (version 8) RFC 9562 UUID
+	id[6] = (id[6] & 0x0f) | 0x80
+	id[8] = (id[8] & 0x3f) | 0x80
+
+	return id.String()
+}
+
+func (a hmacAnonymizer) sum(value string) []byte {
+	mac := hmac.New(sha256.New, a.salt)
+	mac.Write([]byte(value))
+	return mac.Sum(nil)
+}
+
+type legacyAnonymizer struct{}
+
+// NewLegacyAnonymizer returns the unsalted MD5 based Anonymizer of earlier
+// releases. Its identifiers can be reversed for guessable names, it only
+// exists to stay comparable with data reported before a salt was set up.
+func NewLegacyAnonymizer() Anonymizer {
+	return legacyAnonymizer{}
+}
+
+func (legacyAnonymizer) ID(value string) string {
+	sum := md5.Sum([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
+
+func (legacyAnonymizer) UUID(value string) string {
+	return uuid.NewMD5(uuid.Nil, []byte(value)).String()
+}
+
+// NewAnonymizerFromEnv returns an Anonymizer keyed with the salt from
+// AnonymizationSaltEnv. Without a salt every installation would report the
+// same identifiers for the same names, so an error is returned instead,
+// unless legacy explicitly selects the legacy Anonymizer.
+func NewAnonymizerFromEnv(legacy bool, log *zap.SugaredLogger) (Anonymizer, error) {
+	if legacy {
+		log.Warn("Using the legacy anonymization, identifiers of guessable names can be reversed")
+		return NewLegacyAnonymizer(), nil
+	}
+
+	salt := os.Getenv(AnonymizationSaltEnv)
+	if salt == "" {
+		return nil, fmt.Errorf("no anonymization salt set in %s", AnonymizationSaltEnv)
+	}
+
+	return NewAnonymizer([]byte(salt)), nil
+}
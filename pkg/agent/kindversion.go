@@ -16,6 +16,13 @@ limitations under the License.
 
 package agent
 
+const (
+	// KindKubernetes is the kind of records collected by the kubernetes agent.
+	KindKubernetes = "kubernetes"
+	// KindKubermatic is the kind of records collected by the kubermatic agent.
+	KindKubermatic = "kubermatic"
+)
+
 type KindVersion struct {
 	// Kind the kind of this Agent.
 	Kind string `json:"kind"`
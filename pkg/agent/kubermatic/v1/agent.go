@@ -27,7 +27,6 @@ import (
 	"github.com/kubermatic/telemetry-client/pkg/datastore"
 	telemetryversion "github.com/kubermatic/telemetry-client/pkg/version"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
@@ -44,15 +43,17 @@ type serverVersionInfo interface {
 type kubermaticAgent struct {
 	client.Client
 	serverVersionInfo
-	dataStore datastore.DataStore
-	log       *zap.SugaredLogger
+	dataStore  datastore.DataStore
+	anonymizer agent.Anonymizer
+	log        *zap.SugaredLogger
 }
 
-func NewAgent(client client.Client, info serverVersionInfo, dataStore datastore.DataStore, log *zap.SugaredLogger) agent.Agent {
+func NewAgent(client client.Client, info serverVersionInfo, dataStore datastore.DataStore, anonymizer agent.Anonymizer, log *zap.SugaredLogger) agent.Agent {
 	return kubermaticAgent{
 		Client:            client,
 		serverVersionInfo: info,
 		dataStore:         dataStore,
+		anonymizer:        anonymizer,
 		log:               log,
 	}
 }
@@ -67,7 +68,7 @@ func (a kubermaticAgent) Collect(ctx context.Context) error {
 	}
 	record := v1types.Record{
 		KindVersion: agent.KindVersion{
-			Kind:    "kubermatic",
+			Kind:    agent.KindKubermatic,
 			Version: telemetryversion.V1Version,
 		},
 		Time:              time.Now().UTC(),
@@ -86,7 +87,7 @@ func (a kubermaticAgent) Collect(ctx context.Context) error {
 	}
 
 	for _, project := range projectList.Items {
-		project, err := projectFromKube(project)
+		project, err := projectFromKube(a.anonymizer, project)
 		if err != nil {
 			return err
 		}
@@ -102,7 +103,7 @@ func (a kubermaticAgent) Collect(ctx context.Context) error {
 	}
 
 	for _, user := range userList.Items {
-		user, err := userKeyFromKube(user)
+		user, err := userKeyFromKube(a.anonymizer, user)
 		if err != nil {
 			return err
 		}
@@ -118,7 +119,7 @@ func (a kubermaticAgent) Collect(ctx context.Context) error {
 	}
 
 	for _, sshKey := range sshKeyList.Items {
-		sshKey, err := sshKeyFromKube(sshKey)
+		sshKey, err := sshKeyFromKube(a.anonymizer, sshKey)
 		if err != nil {
 			return err
 		}
@@ -150,7 +151,7 @@ func (a kubermaticAgent) Collect(ctx context.Context) error {
 		}
 
 		for _, cluster := range clusterList.Items {
-			cluster, err := clusterFromKube(cluster, seed.Name)
+			cluster, err := clusterFromKube(a.anonymizer, cluster, seed.Name)
 			if err != nil {
 				return err
 			}
@@ -159,7 +160,7 @@ func (a kubermaticAgent) Collect(ctx context.Context) error {
 
 		a.log.Infow("Collected userclusters", "seed", seed.Name, "clusters", len(record.Clusters))
 
-		seed, err := seedFromKube(seed, defaultExposeStrategy)
+		seed, err := seedFromKube(a.anonymizer, seed, defaultExposeStrategy)
 		if err != nil {
 			return err
 		}
@@ -193,7 +194,7 @@ func (a kubermaticAgent) getDefaultExposeStrategy(ctx context.Context) (kubermat
 	return defaultExposeStrategy, nil
 }
 
-func seedFromKube(kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.ExposeStrategy) (v1types.Seed, error) {
+func seedFromKube(anonymizer agent.Anonymizer, kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.ExposeStrategy) (v1types.Seed, error) {
 	var kDatacenter []v1types.Datacenter
 
 	datacenters := kSeed.Spec.Datacenters
@@ -204,7 +205,7 @@ func seedFromKube(kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.Ex
 		}
 
 		kDatacenter = append(kDatacenter, v1types.Datacenter{
-			UUID:     generateUUID(name),
+			UUID:     anonymizer.UUID(name),
 			Country:  datacenter.Country,
 			Location: datacenter.Location,
 			Provider: providerName,
@@ -217,7 +218,7 @@ func seedFromKube(kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.Ex
 		exposeStrategy = defaultExposeStrategy
 	}
 	seed := v1types.Seed{
-		UUID:           generateUUID(kSeed.Name),
+		UUID:           anonymizer.UUID(kSeed.Name),
 		Country:        kSeed.Spec.Country,
 		Location:       kSeed.Spec.Location,
 		ExposeStrategy: string(exposeStrategy),
@@ -227,7 +228,7 @@ func seedFromKube(kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.Ex
 	return seed, nil
 }
 
-func clusterFromKube(kn kubermaticv1.Cluster, seedName string) (v1types.Cluster, error) {
+func clusterFromKube(anonymizer agent.Anonymizer, kn kubermaticv1.Cluster, seedName string) (v1types.Cluster, error) {
 	providerName, err := kubermaticv1helper.ClusterCloudProviderName(kn.Spec.Cloud)
 	if err != nil {
 		return v1types.Cluster{}, err
@@ -257,15 +258,15 @@ func clusterFromKube(kn kubermaticv1.Cluster, seedName string) (v1types.Cluster,
 		etcdSize = int(*kn.Spec.ComponentsOverride.Etcd.ClusterSize)
 	}
 	cluster := v1types.Cluster{
-		UUID:                    generateUUID(kn.Name),
-		SeedUUID:                generateUUID(seedName),
-		ProjectUUID:             generateUUID(kn.Labels[kubermaticv1.ProjectIDLabelKey]),
+		UUID:                    anonymizer.UUID(kn.Name),
+		SeedUUID:                anonymizer.UUID(seedName),
+		ProjectUUID:             anonymizer.UUID(kn.Labels[kubermaticv1.ProjectIDLabelKey]),
 		ExposeStrategy:          string(kn.Spec.ExposeStrategy),
 		EtcdClusterSize:         etcdSize,
 		KubernetesServerVersion: kn.Spec.Version.String(),
 		Cloud: v1types.Cloud{
 			ProviderName:   providerName,
-			DatacenterUUID: generateUUID(kn.Spec.Cloud.DatacenterName),
+			DatacenterUUID: anonymizer.UUID(kn.Spec.Cloud.DatacenterName),
 		},
 		OPAIntegrationEnabled:  opaEnabled,
 		UserSSHKeyAgentEnabled: enableUserSSHKeyAgent,
@@ -274,49 +275,43 @@ func clusterFromKube(kn kubermaticv1.Cluster, seedName string) (v1types.Cluster,
 	return cluster, nil
 }
 
-func projectFromKube(kn kubermaticv1.Project) (v1types.Project, error) {
+func projectFromKube(anonymizer agent.Anonymizer, kn kubermaticv1.Project) (v1types.Project, error) {
 	project := v1types.Project{
-		UUID: generateUUID(kn.Name),
+		UUID: anonymizer.UUID(kn.Name),
 	}
 	return project, nil
 }
 
-func sshKeyFromKube(kn kubermaticv1.UserSSHKey) (v1types.SSHKey, error) {
+func sshKeyFromKube(anonymizer agent.Anonymizer, kn kubermaticv1.UserSSHKey) (v1types.SSHKey, error) {
 	var ownerProject string
 	for _, ownerReference := range kn.OwnerReferences {
 		if ownerReference.Kind == kubermaticv1.ProjectKindName {
-			ownerProject = generateUUID(ownerReference.Name)
+			ownerProject = anonymizer.UUID(ownerReference.Name)
 			break
 		}
 	}
 
 	var clusters []string
 	for _, cluster := range kn.Spec.Clusters {
-		clusters = append(clusters, generateUUID(cluster))
+		clusters = append(clusters, anonymizer.UUID(cluster))
 	}
 
 	SSHKey := v1types.SSHKey{
-		UUID:             generateUUID(kn.Name),
+		UUID:             anonymizer.UUID(kn.Name),
 		OwnerProjectUUID: ownerProject,
 		ClusterUUIDs:     clusters,
 	}
 	return SSHKey, nil
 }
 
-func userKeyFromKube(kn kubermaticv1.User) (v1types.User, error) {
+func userKeyFromKube(anonymizer agent.Anonymizer, kn kubermaticv1.User) (v1types.User, error) {
 	user := v1types.User{
-		UUID:    generateUUID(kn.Name),
+		UUID:    anonymizer.UUID(kn.Name),
 		IsAdmin: kn.Spec.IsAdmin,
 	}
 	return user, nil
 }
 
-func generateUUID(x string) string {
-	UUID := uuid.NewMD5(uuid.Nil, []byte(x))
-
-	return UUID.String()
-}
-
 func datacenterCloudRegionName(spec *kubermaticv1.DatacenterSpec, providerName string) string {
 	if spec == nil {
 		return ""
@@ -19,7 +19,7 @@ package v2
 import (
 	"context"
 	"encoding/json"
-	"fmt"
+	"errors"
 	"time"
 
 	"github.com/kubermatic/telemetry-client/pkg/agent"
@@ -27,13 +27,15 @@ import (
 	"github.com/kubermatic/telemetry-client/pkg/datastore"
 	telemetryversion "github.com/kubermatic/telemetry-client/pkg/version"
 
-	"github.com/google/uuid"
 	"go.uber.org/zap"
 	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
 	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
 	"k8c.io/kubermatic/v2/pkg/defaulting"
+	"k8c.io/kubermatic/v2/pkg/provider"
 	kubernetesprovider "k8c.io/kubermatic/v2/pkg/provider/kubernetes"
 	"k8c.io/kubermatic/v2/pkg/resources"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/version"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -42,142 +44,211 @@ type serverVersionInfo interface {
 	ServerVersion() (*version.Info, error)
 }
 
+const (
+	// DefaultSeedWorkers is the default number of seeds collected concurrently.
+	DefaultSeedWorkers = 4
+	// DefaultSeedTimeout is the default time budget for listing the clusters
+	// of a single seed.
+	DefaultSeedTimeout = 5 * time.Minute
+	// DefaultUserClusterWorkers is the default number of user clusters of a
+	// seed visited concurrently.
+	DefaultUserClusterWorkers = 8
+	// DefaultUserClusterTimeout is the default time budget for visiting the
+	// user clusters of a single seed.
+	DefaultUserClusterTimeout = 10 * time.Minute
+)
+
+// Options tunes the collection of large installations.
+type Options struct {
+	// SeedWorkers is the number of seeds collected concurrently.
+	SeedWorkers int
+	// SeedTimeout bounds describing a single seed and listing its clusters.
+	SeedTimeout time.Duration
+	// UserClusterWorkers is the number of user clusters of a seed visited
+	// concurrently.
+	UserClusterWorkers int
+	// UserClusterTimeout bounds visiting the user clusters of a single
+	// seed, after all of its clusters were listed.
+	UserClusterTimeout time.Duration
+	// PageSize is the number of objects requested per List call.
+	PageSize int64
+}
+
 type kubermaticAgent struct {
 	client.Client
 	serverVersionInfo
 
-	dataStore datastore.DataStore
-	log       *zap.SugaredLogger
+	dataStore  datastore.DataStore
+	anonymizer agent.Anonymizer
+	opts       Options
+	log        *zap.SugaredLogger
+
+	// seedClientGetter, if set, is used instead of connecting to the seeds
+	// via their kubeconfig Secrets.
+	seedClientGetter provider.SeedClientGetter
 }
 
-func NewAgent(client client.Client, info serverVersionInfo, dataStore datastore.DataStore, log *zap.SugaredLogger) agent.Agent {
+func NewAgent(client client.Client, info serverVersionInfo, dataStore datastore.DataStore, anonymizer agent.Anonymizer, opts Options, log *zap.SugaredLogger) agent.Agent {
+	if opts.SeedWorkers <= 0 {
+		opts.SeedWorkers = DefaultSeedWorkers
+	}
+	if opts.SeedTimeout <= 0 {
+		opts.SeedTimeout = DefaultSeedTimeout
+	}
+	if opts.UserClusterWorkers <= 0 {
+		opts.UserClusterWorkers = DefaultUserClusterWorkers
+	}
+	if opts.UserClusterTimeout <= 0 {
+		opts.UserClusterTimeout = DefaultUserClusterTimeout
+	}
+	if opts.PageSize <= 0 {
+		opts.PageSize = agent.DefaultPageSize
+	}
+
+	if unmapped := UnmappedProviders(); len(unmapped) > 0 {
+		log.Warnw("Locations of some providers are unknown and will not be reported", "providers", unmapped)
+	}
+
 	return kubermaticAgent{
 		Client:            client,
 		serverVersionInfo: info,
 		dataStore:         dataStore,
+		anonymizer:        anonymizer,
+		opts:              opts,
 		log:               log,
 	}
 }
 
-// +kubebuilder:rbac:groups="kubermatic.k8c.io",resources=seeds;clusters;users;projects;usersshkeys;kubermaticconfigurations,verbs=list
+// +kubebuilder:rbac:groups="kubermatic.k8c.io",resources=seeds;clusters;externalclusters;addons;users;projects;userprojectbindings;groupprojectbindings;usersshkeys;kubermaticconfigurations,verbs=list
 // +kubebuilder:rbac:groups="",resources=secrets,verbs=get
 
 func (a kubermaticAgent) Collect(ctx context.Context) error {
 	record := v2types.Record{
 		KindVersion: agent.KindVersion{
-			Kind:    "kubermatic",
+			Kind:    agent.KindKubermatic,
 			Version: telemetryversion.V2Version,
 		},
 		Time: time.Now().UTC(),
 	}
 
 	// Get Kubermatic Configuration
-	configGetter, err := kubernetesprovider.DynamicKubermaticConfigurationGetterFactory(a.Client, resources.KubermaticNamespace)
-	if err != nil {
-		return err
-	}
-	config, err := configGetter(ctx)
+	defaultExposeStrategy := defaulting.DefaultExposeStrategy
+	config, err := a.kubermaticConfiguration(ctx)
 	if err != nil {
-		return err
-	}
-	// Get Kubermatic Configuration fields
-	record.KubermaticEdition = config.Status.KubermaticEdition
-	record.KubermaticVersion = config.Status.KubermaticVersion
+		a.addError(&record.Errors, "", "kubermaticconfigurations", "", err)
+	} else {
+		// Get Kubermatic Configuration fields
+		record.KubermaticEdition = config.Status.KubermaticEdition
+		record.KubermaticVersion = config.Status.KubermaticVersion
+
+		if config.Spec.ExposeStrategy != "" {
+			defaultExposeStrategy = config.Spec.ExposeStrategy
+		}
 
-	defaultExposeStrategy := config.Spec.ExposeStrategy
-	if defaultExposeStrategy == "" {
-		defaultExposeStrategy = defaulting.DefaultExposeStrategy
+		record.Configuration = configurationFromKube(*config)
 	}
+	record.Configuration.ExposeStrategy = string(defaultExposeStrategy)
 
-	// List projects
-	projectList := &kubermaticv1.ProjectList{}
-	if err := a.List(ctx, projectList); err != nil {
-		return fmt.Errorf("failed listing projects: %w", err)
+	// List projects, only their names are needed
+	newProjectList := func() *metav1.PartialObjectMetadataList {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(kubermaticv1.SchemeGroupVersion.WithKind("ProjectList"))
+		return list
 	}
-
-	for _, project := range projectList.Items {
-		project, err := projectFromKube(project)
-		if err != nil {
-			return err
+	if err := agent.ListPages(ctx, a.Client, newProjectList, a.opts.PageSize, func(projectList *metav1.PartialObjectMetadataList) error {
+		for _, kProject := range projectList.Items {
+			project, err := projectFromKube(a.anonymizer, kProject)
+			if err != nil {
+				a.addError(&record.Errors, "", "projects", a.anonymizer.UUID(kProject.Name), err)
+				continue
+			}
+			record.Projects = append(record.Projects, project)
 		}
-		record.Projects = append(record.Projects, project)
+		return nil
+	}); err != nil {
+		a.addError(&record.Errors, "", "projects", "", err)
 	}
 
 	a.log.Infow("Collected projects", "projects", len(record.Projects))
 
-	// List users
-	userList := &kubermaticv1.UserList{}
-	if err := a.List(ctx, userList); err != nil {
-		return fmt.Errorf("failed listing users: %w", err)
-	}
-
-	for _, user := range userList.Items {
-		user, err := userKeyFromKube(user)
-		if err != nil {
-			return err
+	// List users, paged as full objects because the admin flag is part of the spec
+	newUserList := func() *kubermaticv1.UserList { return &kubermaticv1.UserList{} }
+	if err := agent.ListPages(ctx, a.Client, newUserList, a.opts.PageSize, func(userList *kubermaticv1.UserList) error {
+		for _, kUser := range userList.Items {
+			user, err := userKeyFromKube(a.anonymizer, kUser)
+			if err != nil {
+				a.addError(&record.Errors, "", "users", a.anonymizer.UUID(kUser.Name), err)
+				continue
+			}
+			record.Users = append(record.Users, user)
 		}
-		record.Users = append(record.Users, user)
+		return nil
+	}); err != nil {
+		a.addError(&record.Errors, "", "users", "", err)
 	}
 
 	a.log.Infow("Collected users", "users", len(record.Users))
 
-	// List sshKeys
-	sshKeyList := &kubermaticv1.UserSSHKeyList{}
-	if err := a.List(ctx, sshKeyList); err != nil {
-		return fmt.Errorf("failed listing ssh keys: %w", err)
-	}
+	a.collectProjectMembers(ctx, record.Projects, &record.Errors)
 
-	for _, sshKey := range sshKeyList.Items {
-		sshKey, err := sshKeyFromKube(sshKey)
-		if err != nil {
-			return err
+	// List sshKeys, paged as full objects because the clusters are part of the spec
+	newSSHKeyList := func() *kubermaticv1.UserSSHKeyList { return &kubermaticv1.UserSSHKeyList{} }
+	if err := agent.ListPages(ctx, a.Client, newSSHKeyList, a.opts.PageSize, func(sshKeyList *kubermaticv1.UserSSHKeyList) error {
+		for _, kSSHKey := range sshKeyList.Items {
+			sshKey, err := sshKeyFromKube(a.anonymizer, kSSHKey)
+			if err != nil {
+				a.addError(&record.Errors, "", "usersshkeys", a.anonymizer.UUID(kSSHKey.Name), err)
+				continue
+			}
+			record.SSHKeys = append(record.SSHKeys, sshKey)
 		}
-		record.SSHKeys = append(record.SSHKeys, sshKey)
+		return nil
+	}); err != nil {
+		a.addError(&record.Errors, "", "usersshkeys", "", err)
 	}
 
 	a.log.Infow("Collected SSH keys", "keys", len(record.SSHKeys))
 
+	record.ExternalClusters = a.collectExternalClusters(ctx, &record.Errors)
+
+	a.log.Infow("Collected external clusters", "clusters", len(record.ExternalClusters))
+
 	// List seeds
-	seedList := &kubermaticv1.SeedList{}
-	if err := a.List(ctx, seedList); err != nil {
-		return fmt.Errorf("failed listing seeds: %w", err)
+	var seeds []kubermaticv1.Seed
+	newSeedList := func() *kubermaticv1.SeedList { return &kubermaticv1.SeedList{} }
+	if err := agent.ListPages(ctx, a.Client, newSeedList, a.opts.PageSize, func(seedList *kubermaticv1.SeedList) error {
+		seeds = append(seeds, seedList.Items...)
+		return nil
+	}); err != nil {
+		a.addError(&record.Errors, "", "seeds", "", err)
 	}
 
-	for _, seed := range seedList.Items {
+	seedClientGetter := a.seedClientGetter
+	if seedClientGetter == nil {
 		seedKubeconfigGetter, err := kubernetesprovider.SeedKubeconfigGetterFactory(ctx, a.Client)
 		if err != nil {
-			return err
-		}
-		seedClientGetter := kubernetesprovider.SeedClientGetterFactory(seedKubeconfigGetter)
-		seedClient, err := seedClientGetter(&seed)
-		if err != nil {
-			return fmt.Errorf("failed getting seed client for seed %s: %w", seed.Name, err)
-		}
-
-		//  List clusters per seed
-		clusterList := &kubermaticv1.ClusterList{}
-		if err := seedClient.List(ctx, clusterList); err != nil {
-			return fmt.Errorf("failed listing clusters: %w", err)
+			// everything collected from the master cluster is still reported
+			a.addError(&record.Errors, "", "seeds", "", err)
+		} else {
+			// a single getter shares the REST mapper cache between all seeds
+			seedClientGetter = kubernetesprovider.SeedClientGetterFactory(seedKubeconfigGetter)
 		}
+	}
 
-		for _, cluster := range clusterList.Items {
-			cluster, err := clusterFromKube(cluster, seed.Name)
-			if err != nil {
-				return err
+	if seedClientGetter != nil {
+		for _, result := range a.collectSeeds(ctx, seeds, seedClientGetter, defaultExposeStrategy) {
+			if result.seed != nil {
+				record.Seeds = append(record.Seeds, *result.seed)
 			}
-			record.Clusters = append(record.Clusters, cluster)
+			record.Clusters = append(record.Clusters, result.clusters...)
+			record.Errors = append(record.Errors, result.errors...)
 		}
-
-		a.log.Infow("Collected userclusters", "seed", seed.Name, "clusters", len(record.Clusters))
-
-		seed, err := seedFromKube(seed, defaultExposeStrategy)
-		if err != nil {
-			return err
-		}
-		record.Seeds = append(record.Seeds, seed)
 	}
 
-	a.log.Infow("Collected seeds", "seeds", len(record.Seeds))
+	a.log.Infow("Collected seeds", "seeds", len(record.Seeds), "errors", len(record.Errors))
+
+	record.ApplicationAdoption = applicationAdoption(record.Clusters)
+	record.AddonAdoption = addonAdoption(record.Clusters)
 
 	data, err := json.Marshal(record)
 	if err != nil {
@@ -187,7 +258,38 @@ func (a kubermaticAgent) Collect(ctx context.Context) error {
 	return a.dataStore.Store(ctx, data)
 }
 
-func seedFromKube(kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.ExposeStrategy) (v2types.Seed, error) {
+func (a kubermaticAgent) kubermaticConfiguration(ctx context.Context) (*kubermaticv1.KubermaticConfiguration, error) {
+	configGetter, err := kubernetesprovider.DynamicKubermaticConfigurationGetterFactory(a.Client, resources.KubermaticNamespace)
+	if err != nil {
+		return nil, err
+	}
+
+	return configGetter(ctx)
+}
+
+// addError records that a resource could not be collected. The collection
+// continues, so that a single broken seed or object does not cost the
+// whole record. The error message is only logged, the record just keeps
+// what failed and the reason.
+func (a kubermaticAgent) addError(errs *[]v2types.CollectionError, seedUUID, resource, objectUUID string, err error) {
+	a.log.Warnw("Failed to collect data", "seed", seedUUID, "resource", resource, "object", objectUUID, "error", err)
+
+	collectionErr := v2types.CollectionError{
+		SeedUUID:   seedUUID,
+		Resource:   resource,
+		ObjectUUID: objectUUID,
+	}
+
+	if reason := apierrors.ReasonForError(err); reason != metav1.StatusReasonUnknown {
+		collectionErr.Reason = string(reason)
+	} else if errors.Is(err, context.DeadlineExceeded) {
+		collectionErr.Reason = string(metav1.StatusReasonTimeout)
+	}
+
+	*errs = append(*errs, collectionErr)
+}
+
+func seedFromKube(anonymizer agent.Anonymizer, kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.ExposeStrategy) (v2types.Seed, error) {
 	var kDatacenter []v2types.Datacenter
 
 	datacenters := kSeed.Spec.Datacenters
@@ -198,11 +300,12 @@ func seedFromKube(kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.Ex
 		}
 
 		kDatacenter = append(kDatacenter, v2types.Datacenter{
-			UUID:     generateUUID(name),
-			Country:  datacenter.Country,
-			Location: datacenter.Location,
-			Provider: providerName,
-			Region:   datacenterCloudRegionName(&datacenter.Spec, providerName),
+			UUID:            anonymizer.UUID(name),
+			Country:         datacenter.Country,
+			Location:        datacenter.Location,
+			Provider:        providerName,
+			Region:          datacenterCloudRegionName(&datacenter.Spec, providerName),
+			SubLocationUUID: anonymizeOptional(anonymizer, datacenterSubLocation(&datacenter.Spec, providerName)),
 		})
 	}
 
@@ -211,17 +314,47 @@ func seedFromKube(kSeed kubermaticv1.Seed, defaultExposeStrategy kubermaticv1.Ex
 		exposeStrategy = defaultExposeStrategy
 	}
 	seed := v2types.Seed{
-		UUID:           generateUUID(kSeed.Name),
-		Country:        kSeed.Spec.Country,
-		Location:       kSeed.Spec.Location,
-		ExposeStrategy: string(exposeStrategy),
-		Datacenters:    kDatacenter,
+		UUID:                 anonymizer.UUID(kSeed.Name),
+		Country:              kSeed.Spec.Country,
+		Location:             kSeed.Spec.Location,
+		ExposeStrategy:       string(exposeStrategy),
+		Datacenters:          kDatacenter,
+		NodePortProxyEnabled: !kSeed.Spec.NodeportProxy.Disable,
+	}
+
+	if kSeed.Spec.MLA != nil {
+		seed.MLAEnabled = kSeed.Spec.MLA.UserClusterMLAEnabled
+	}
+	if kSeed.Spec.EtcdBackupRestore != nil {
+		seed.EtcdBackupDestinations = len(kSeed.Spec.EtcdBackupRestore.Destinations)
+		seed.EtcdBackupRestoreEnabled = seed.EtcdBackupDestinations > 0
+	}
+	if kSeed.Spec.Metering != nil {
+		seed.MeteringEnabled = kSeed.Spec.Metering.Enabled
+	}
+	if kSeed.Spec.ProxySettings != nil {
+		seed.ProxyEnabled = !kSeed.Spec.ProxySettings.HTTPProxy.Empty()
 	}
 
 	return seed, nil
 }
 
-func clusterFromKube(kn kubermaticv1.Cluster, seedName string) (v2types.Cluster, error) {
+func configurationFromKube(config kubermaticv1.KubermaticConfiguration) v2types.Configuration {
+	configuration := v2types.Configuration{
+		ProxyEnabled: config.Spec.Proxy.HTTP != "" || config.Spec.Proxy.HTTPS != "",
+	}
+
+	if len(config.Spec.FeatureGates) > 0 {
+		configuration.FeatureGates = make(map[string]bool, len(config.Spec.FeatureGates))
+		for name, enabled := range config.Spec.FeatureGates {
+			configuration.FeatureGates[name] = enabled
+		}
+	}
+
+	return configuration
+}
+
+func clusterFromKube(anonymizer agent.Anonymizer, kn kubermaticv1.Cluster, seedName string) (v2types.Cluster, error) {
 	providerName, err := kubermaticv1helper.ClusterCloudProviderName(kn.Spec.Cloud)
 	if err != nil {
 		return v2types.Cluster{}, err
@@ -267,48 +400,109 @@ func clusterFromKube(kn kubermaticv1.Cluster, seedName string) (v2types.Cluster,
 		etcdSize = int(*kn.Spec.ComponentsOverride.Etcd.ClusterSize)
 	}
 	cluster := v2types.Cluster{
-		UUID:                    generateUUID(kn.Name),
-		SeedUUID:                generateUUID(seedName),
-		ProjectUUID:             generateUUID(kn.Labels[kubermaticv1.ProjectIDLabelKey]),
+		UUID:                    anonymizer.UUID(kn.Name),
+		SeedUUID:                anonymizer.UUID(seedName),
+		ProjectUUID:             anonymizer.UUID(kn.Labels[kubermaticv1.ProjectIDLabelKey]),
 		CNIPlugin:               cniPlugin,
 		ClusterNetwork:          clusterNetworkingConfig,
 		ExposeStrategy:          string(kn.Spec.ExposeStrategy),
 		EtcdClusterSize:         etcdSize,
 		KubernetesServerVersion: kn.Spec.Version.String(),
 		Cloud: v2types.Cloud{
-			ProviderName:   providerName,
-			DatacenterUUID: generateUUID(kn.Spec.Cloud.DatacenterName),
+			ProviderName:    providerName,
+			DatacenterUUID:  anonymizer.UUID(kn.Spec.Cloud.DatacenterName),
+			SubLocationUUID: anonymizeOptional(anonymizer, clusterSubLocation(kn.Spec.Cloud, providerName)),
 		},
 		OPAIntegrationEnabled:  opaEnabled,
 		UserSSHKeyAgentEnabled: userSSHKeyAgentEnabled,
 		MLA:                    mla,
+		Phase:                  string(kn.Status.Phase),
+		Health:                 clusterHealthFromKube(kn.Status.ExtendedHealth),
+		AgeBucket:              clusterAgeBucket(time.Since(kn.CreationTimestamp.Time)),
+		Deleting:               kn.DeletionTimestamp != nil,
+		// the control plane version is not known before it is deployed
+		UpdatePending: kn.Status.Versions.ControlPlane != "" && !kn.Spec.Version.Equal(&kn.Status.Versions.ControlPlane),
 	}
 	return cluster, nil
 }
 
-func projectFromKube(kn kubermaticv1.Project) (v2types.Project, error) {
+func clusterHealthFromKube(health kubermaticv1.ExtendedClusterHealth) v2types.ClusterHealth {
+	optional := func(status *kubermaticv1.HealthStatus) string {
+		if status == nil {
+			return ""
+		}
+		return string(*status)
+	}
+
+	return v2types.ClusterHealth{
+		Apiserver:                    string(health.Apiserver),
+		Scheduler:                    string(health.Scheduler),
+		Controller:                   string(health.Controller),
+		MachineController:            string(health.MachineController),
+		Etcd:                         string(health.Etcd),
+		OpenVPN:                      string(health.OpenVPN),
+		Konnectivity:                 string(health.Konnectivity),
+		CloudProviderInfrastructure:  string(health.CloudProviderInfrastructure),
+		UserClusterControllerManager: string(health.UserClusterControllerManager),
+		ApplicationController:        string(health.ApplicationController),
+		GatekeeperController:         optional(health.GatekeeperController),
+		GatekeeperAudit:              optional(health.GatekeeperAudit),
+		Monitoring:                   optional(health.Monitoring),
+		Logging:                      optional(health.Logging),
+		AlertmanagerConfig:           optional(health.AlertmanagerConfig),
+		MLAGateway:                   optional(health.MLAGateway),
+		OperatingSystemManager:       optional(health.OperatingSystemManager),
+		KubernetesDashboard:          optional(health.KubernetesDashboard),
+	}
+}
+
+// clusterAgeBuckets are the upper bounds of the reported cluster ages.
+var clusterAgeBuckets = []struct {
+	maxAge time.Duration
+	name   string
+}{
+	{24 * time.Hour, "<1d"},
+	{7 * 24 * time.Hour, "1d-7d"},
+	{30 * 24 * time.Hour, "7d-30d"},
+	{90 * 24 * time.Hour, "30d-90d"},
+	{365 * 24 * time.Hour, "90d-365d"},
+}
+
+// clusterAgeBucket reports the age coarsely, so that it cannot be used to
+// identify a cluster by its creation time.
+func clusterAgeBucket(age time.Duration) string {
+	for _, bucket := range clusterAgeBuckets {
+		if age < bucket.maxAge {
+			return bucket.name
+		}
+	}
+
+	return ">365d"
+}
+
+func projectFromKube(anonymizer agent.Anonymizer, kn metav1.PartialObjectMetadata) (v2types.Project, error) {
 	project := v2types.Project{
-		UUID: generateUUID(kn.Name),
+		UUID: anonymizer.UUID(kn.Name),
 	}
 	return project, nil
 }
 
-func sshKeyFromKube(kn kubermaticv1.UserSSHKey) (v2types.SSHKey, error) {
+func sshKeyFromKube(anonymizer agent.Anonymizer, kn kubermaticv1.UserSSHKey) (v2types.SSHKey, error) {
 	var ownerProject string
 	for _, ownerReference := range kn.OwnerReferences {
 		if ownerReference.Kind == kubermaticv1.ProjectKindName {
-			ownerProject = generateUUID(ownerReference.Name)
+			ownerProject = anonymizer.UUID(ownerReference.Name)
 			break
 		}
 	}
 
 	var clusters []string
 	for _, cluster := range kn.Spec.Clusters {
-		clusters = append(clusters, generateUUID(cluster))
+		clusters = append(clusters, anonymizer.UUID(cluster))
 	}
 
 	SSHKey := v2types.SSHKey{
-		UUID:             generateUUID(kn.Name),
+		UUID:             anonymizer.UUID(kn.Name),
 		OwnerProjectUUID: ownerProject,
 		ClusterUUIDs:     clusters,
 	}
@@ -316,48 +510,12 @@ func sshKeyFromKube(kn kubermaticv1.UserSSHKey) (v2types.SSHKey, error) {
 	return SSHKey, nil
 }
 
-func userKeyFromKube(kn kubermaticv1.User) (v2types.User, error) {
+func userKeyFromKube(anonymizer agent.Anonymizer, kn kubermaticv1.User) (v2types.User, error) {
 	user := v2types.User{
-		UUID:    generateUUID(kn.Name),
-		IsAdmin: kn.Spec.IsAdmin,
+		UUID:             anonymizer.UUID(kn.Name),
+		IsAdmin:          kn.Spec.IsAdmin,
+		IsServiceAccount: kubermaticv1helper.IsProjectServiceAccount(kn.Spec.Email),
 	}
 
 	return user, nil
 }
-
-func generateUUID(x string) string {
-	UUID := uuid.NewMD5(uuid.Nil, []byte(x))
-
-	return UUID.String()
-}
-
-func datacenterCloudRegionName(spec *kubermaticv1.DatacenterSpec, providerName string) string {
-	if spec == nil {
-		return ""
-	}
-
-	var region string
-
-	switch kubermaticv1.ProviderType(providerName) {
-	case kubermaticv1.DigitaloceanCloudProvider:
-		region = spec.Digitalocean.Region
-	case kubermaticv1.AWSCloudProvider:
-		region = spec.AWS.Region
-	case kubermaticv1.AzureCloudProvider:
-		region = spec.Azure.Location
-	case kubermaticv1.OpenstackCloudProvider:
-		region = spec.Openstack.Region
-	case kubermaticv1.HetznerCloudProvider:
-		region = spec.Hetzner.Location
-	case kubermaticv1.VSphereCloudProvider:
-		region = spec.VSphere.Datacenter
-	case kubermaticv1.GCPCloudProvider:
-		region = spec.GCP.Region
-	case kubermaticv1.AlibabaCloudProvider:
-		region = spec.Alibaba.Region
-	case kubermaticv1.AnexiaCloudProvider:
-		region = spec.Anexia.LocationID
-	}
-
-	return region
-}
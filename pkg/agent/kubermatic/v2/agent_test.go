@@ -0,0 +1,209 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+	"strconv"
+	"testing"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	"github.com/kubermatic/telemetry-client/pkg/datastore"
+
+	"go.uber.org/zap"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/semver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	apiruntime "k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/version"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/fake"
+)
+
+const (
+	fleetUsers    = 50000
+	fleetClusters = 10000
+)
+
+// fleetClient serves a large fleet of projects, users, SSH keys and clusters
+// page by page. The objects are generated for every page, so that the heap
+// only grows with what the agent keeps. Everything else is read from the
+// wrapped client.
+type fleetClient struct {
+	client.Client
+
+	// peakHeap is the largest heap size seen by any List call.
+	peakHeap uint64
+}
+
+func (c *fleetClient) List(ctx context.Context, list client.ObjectList, opts ...client.ListOption) error {
+	var (
+		total int
+		add   func(i int)
+	)
+
+	switch l := list.(type) {
+	case *metav1.PartialObjectMetadataList:
+		if l.GroupVersionKind().Kind != "ProjectList" {
+			return c.Client.List(ctx, list, opts...)
+		}
+		total = fleetUsers / 5
+		add = func(i int) {
+			l.Items = append(l.Items, metav1.PartialObjectMetadata{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("project-%d", i)},
+			})
+		}
+
+	case *kubermaticv1.UserList:
+		total = fleetUsers
+		add = func(i int) {
+			l.Items = append(l.Items, kubermaticv1.User{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("user-%d", i)},
+				Spec: kubermaticv1.UserSpec{
+					Name:  fmt.Sprintf("User %d", i),
+					Email: fmt.Sprintf("user-%d@example.com", i),
+				},
+			})
+		}
+
+	case *kubermaticv1.UserSSHKeyList:
+		total = fleetUsers
+		add = func(i int) {
+			l.Items = append(l.Items, kubermaticv1.UserSSHKey{
+				ObjectMeta: metav1.ObjectMeta{Name: fmt.Sprintf("key-%d", i)},
+				Spec: kubermaticv1.SSHKeySpec{
+					Owner:     fmt.Sprintf("user-%d@example.com", i),
+					Project:   fmt.Sprintf("project-%d", i%(fleetUsers/5)),
+					Clusters:  []string{fmt.Sprintf("cluster-%d", i%fleetClusters)},
+					PublicKey: "ssh-ed25519 AAAAC3NzaC1lZDI1NTE5AAAAIPLACEHOLDERPLACEHOLDERPLACEHOLDERPLACEH",
+				},
+			})
+		}
+
+	case *kubermaticv1.ClusterList:
+		total = fleetClusters
+		add = func(i int) {
+			l.Items = append(l.Items, kubermaticv1.Cluster{
+				ObjectMeta: metav1.ObjectMeta{
+					Name:   fmt.Sprintf("cluster-%d", i),
+					Labels: map[string]string{kubermaticv1.ProjectIDLabelKey: fmt.Sprintf("project-%d", i%(fleetUsers/5))},
+				},
+				Spec: kubermaticv1.ClusterSpec{
+					HumanReadableName: fmt.Sprintf("Cluster %d", i),
+					Version:           *semver.NewSemverOrDie("1.27.3"),
+					ExposeStrategy:    kubermaticv1.ExposeStrategyNodePort,
+					Cloud: kubermaticv1.CloudSpec{
+						DatacenterName: "byo",
+						ProviderName:   string(kubermaticv1.BringYourOwnCloudProvider),
+						BringYourOwn:   &kubermaticv1.BringYourOwnCloudSpec{},
+					},
+				},
+			})
+		}
+
+	default:
+		return c.Client.List(ctx, list, opts...)
+	}
+
+	c.recordHeap()
+
+	listOpts := &client.ListOptions{}
+	listOpts.ApplyOptions(opts)
+
+	start := 0
+	if listOpts.Continue != "" {
+		var err error
+		if start, err = strconv.Atoi(listOpts.Continue); err != nil {
+			return err
+		}
+	}
+	end := total
+	if listOpts.Limit > 0 {
+		end = min(start+int(listOpts.Limit), total)
+	}
+
+	for i := start; i < end; i++ {
+		add(i)
+	}
+	if end < total {
+		list.SetContinue(strconv.Itoa(end))
+	}
+
+	return nil
+}
+
+func (c *fleetClient) recordHeap() {
+	var stats runtime.MemStats
+	runtime.ReadMemStats(&stats)
+	c.peakHeap = max(c.peakHeap, stats.HeapAlloc)
+}
+
+type fakeServerVersion struct{}
+
+func (fakeServerVersion) ServerVersion() (*version.Info, error) {
+	return &version.Info{GitVersion: "v1.27.3"}, nil
+}
+
+// BenchmarkCollect collects a fleet of 50k users and SSH keys, 10k projects
+// and 10k clusters on a single seed. Besides the allocations it reports the
+// largest heap growth seen by a List call, which shrinks with the page size
+// as fewer objects are held at once:
+//
+//	go test -run - -bench Collect -benchmem ./pkg/agent/kubermatic/v2/
+func BenchmarkCollect(b *testing.B) {
+	scheme := apiruntime.NewScheme()
+	if err := kubermaticv1.AddToScheme(scheme); err != nil {
+		b.Fatal(err)
+	}
+
+	seed := &kubermaticv1.Seed{
+		ObjectMeta: metav1.ObjectMeta{Name: "fleet", Namespace: "kubermatic"},
+		Spec: kubermaticv1.SeedSpec{
+			Country:  "DE",
+			Location: "Hamburg",
+			Datacenters: map[string]kubermaticv1.Datacenter{
+				"byo": {Spec: kubermaticv1.DatacenterSpec{BringYourOwn: &kubermaticv1.DatacenterSpecBringYourOwn{}}},
+			},
+		},
+	}
+
+	for _, pageSize := range []int64{100, agent.DefaultPageSize, fleetUsers} {
+		b.Run(fmt.Sprintf("page-size-%d", pageSize), func(b *testing.B) {
+			b.ReportAllocs()
+
+			var peakHeap uint64
+			for i := 0; i < b.N; i++ {
+				c := &fleetClient{Client: fake.NewClientBuilder().WithScheme(scheme).WithObjects(seed).Build()}
+				a := NewAgent(c, fakeServerVersion{}, datastore.NewMemoryStore(), agent.NewAnonymizer([]byte("salt")), Options{PageSize: pageSize}, zap.NewNop().Sugar()).(kubermaticAgent)
+				a.seedClientGetter = func(*kubermaticv1.Seed) (client.Client, error) { return c, nil }
+
+				runtime.GC()
+				c.recordHeap()
+				baseline := c.peakHeap
+
+				if err := a.Collect(context.Background()); err != nil {
+					b.Fatal(err)
+				}
+				peakHeap = max(peakHeap, c.peakHeap-baseline)
+			}
+
+			b.ReportMetric(float64(peakHeap)/(1<<20), "peak-heap-MiB")
+		})
+	}
+}
@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	v2types "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2/types"
+
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// collectApplications returns the catalogue applications installed in the
+// user cluster, sorted by name and version.
+func (a kubermaticAgent) collectApplications(ctx context.Context, userClusterClient client.Client) ([]v2types.Application, error) {
+	var applications []v2types.Application
+	newApplicationList := func() *appskubermaticv1.ApplicationInstallationList {
+		return &appskubermaticv1.ApplicationInstallationList{}
+	}
+	if err := agent.ListPages(ctx, userClusterClient, newApplicationList, a.opts.PageSize, func(list *appskubermaticv1.ApplicationInstallationList) error {
+		for _, installation := range list.Items {
+			applications = append(applications, v2types.Application{
+				Name:    installation.Spec.ApplicationRef.Name,
+				Version: installation.Spec.ApplicationRef.Version,
+			})
+		}
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed listing application installations: %w", err)
+	}
+
+	sort.Slice(applications, func(i, j int) bool {
+		if applications[i].Name != applications[j].Name {
+			return applications[i].Name < applications[j].Name
+		}
+		return applications[i].Version < applications[j].Version
+	})
+
+	return applications, nil
+}
+
+// collectAddons returns the addons in the cluster namespace on the seed,
+// sorted by name.
+func (a kubermaticAgent) collectAddons(ctx context.Context, seedClient client.Client, namespace string) ([]v2types.Addon, error) {
+	var addons []v2types.Addon
+	newAddonList := func() *kubermaticv1.AddonList { return &kubermaticv1.AddonList{} }
+	if err := agent.ListPages(ctx, seedClient, newAddonList, a.opts.PageSize, func(list *kubermaticv1.AddonList) error {
+		for _, addon := range list.Items {
+			addons = append(addons, v2types.Addon{
+				Name:      addon.Spec.Name,
+				IsDefault: addon.Spec.IsDefault,
+			})
+		}
+		return nil
+	}, client.InNamespace(namespace)); err != nil {
+		return nil, fmt.Errorf("failed listing addons: %w", err)
+	}
+
+	sort.Slice(addons, func(i, j int) bool {
+		return addons[i].Name < addons[j].Name
+	})
+
+	return addons, nil
+}
+
+// applicationAdoption counts the clusters every application version is
+// installed in, sorted by name and version.
+func applicationAdoption(clusters []v2types.Cluster) []v2types.ApplicationAdoption {
+	counts := map[v2types.Application]int{}
+	for _, cluster := range clusters {
+		// an application can be installed more than once into the same cluster
+		seen := map[v2types.Application]bool{}
+		for _, application := range cluster.Applications {
+			if !seen[application] {
+				seen[application] = true
+				counts[application]++
+			}
+		}
+	}
+
+	result := make([]v2types.ApplicationAdoption, 0, len(counts))
+	for application, count := range counts {
+		result = append(result, v2types.ApplicationAdoption{
+			Name:     application.Name,
+			Version:  application.Version,
+			Clusters: count,
+		})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		if result[i].Name != result[j].Name {
+			return result[i].Name < result[j].Name
+		}
+		return result[i].Version < result[j].Version
+	})
+
+	return result
+}
+
+// addonAdoption counts the clusters every addon is installed in, sorted by name.
+func addonAdoption(clusters []v2types.Cluster) []v2types.AddonAdoption {
+	counts := map[string]int{}
+	for _, cluster := range clusters {
+		for _, addon := range cluster.Addons {
+			counts[addon.Name]++
+		}
+	}
+
+	result := make([]v2types.AddonAdoption, 0, len(counts))
+	for name, count := range counts {
+		result = append(result, v2types.AddonAdoption{Name: name, Clusters: count})
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Name < result[j].Name
+	})
+
+	return result
+}
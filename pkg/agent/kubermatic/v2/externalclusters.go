@@ -0,0 +1,131 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	v2types "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2/types"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// externalClusterMapper maps the only kind read from external clusters.
+var externalClusterMapper = newStaticRESTMapper(meta.RESTScopeRoot, corev1.SchemeGroupVersion.WithKind("Node"))
+
+// collectExternalClusters collects the clusters imported into or provisioned
+// by KKP, including the number of nodes of every reachable cluster.
+func (a kubermaticAgent) collectExternalClusters(ctx context.Context, errs *[]v2types.CollectionError) []v2types.ExternalCluster {
+	var externalClusters []v2types.ExternalCluster
+	newExternalClusterList := func() *kubermaticv1.ExternalClusterList { return &kubermaticv1.ExternalClusterList{} }
+	if err := agent.ListPages(ctx, a.Client, newExternalClusterList, a.opts.PageSize, func(list *kubermaticv1.ExternalClusterList) error {
+		for _, kCluster := range list.Items {
+			cluster := externalClusterFromKube(a.anonymizer, kCluster)
+
+			// paused clusters are not reconciled and likely not reachable
+			if kCluster.Spec.KubeconfigReference != nil && !kCluster.Spec.Pause {
+				nodes, err := a.countExternalClusterNodes(ctx, kCluster)
+				if err != nil {
+					a.addError(errs, "", "nodes", cluster.UUID, err)
+				} else {
+					cluster.NodeCount = &nodes
+				}
+			}
+
+			externalClusters = append(externalClusters, cluster)
+		}
+		return nil
+	}); err != nil {
+		a.addError(errs, "", "externalclusters", "", err)
+	}
+
+	return externalClusters
+}
+
+func externalClusterFromKube(anonymizer agent.Anonymizer, kn kubermaticv1.ExternalCluster) v2types.ExternalCluster {
+	// the project is the owner, older clusters might only carry the label
+	projectID := kn.Labels[kubermaticv1.ProjectIDLabelKey]
+	for _, owner := range kn.OwnerReferences {
+		if owner.Kind == kubermaticv1.ProjectKindName {
+			projectID = owner.Name
+			break
+		}
+	}
+
+	cluster := v2types.ExternalCluster{
+		UUID:              anonymizer.UUID(kn.Name),
+		ProjectUUID:       anonymizeOptional(anonymizer, projectID),
+		Provider:          string(kn.Spec.CloudSpec.ProviderName),
+		KubernetesVersion: kn.Spec.Version.String(),
+		Phase:             string(kn.Status.Condition.Phase),
+	}
+
+	if kn.Spec.CloudSpec.KubeOne != nil {
+		cluster.KubeOneProvider = kn.Spec.CloudSpec.KubeOne.ProviderName
+	}
+
+	return cluster
+}
+
+// countExternalClusterNodes connects to the external cluster via the
+// kubeconfig KKP keeps for it and counts its nodes.
+func (a kubermaticAgent) countExternalClusterNodes(ctx context.Context, kCluster kubermaticv1.ExternalCluster) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, userClusterTimeout)
+	defer cancel()
+
+	ref := kCluster.Spec.KubeconfigReference
+	secretKey := ref.Key
+	if secretKey == "" {
+		secretKey = resources.KubeconfigSecretKey
+	}
+
+	secret := &corev1.Secret{}
+	if err := a.Get(ctx, client.ObjectKey{Namespace: ref.Namespace, Name: ref.Name}, secret); err != nil {
+		return 0, fmt.Errorf("failed to get kubeconfig: %w", err)
+	}
+
+	externalClusterClient, closeClient, err := newClusterClient(secret.Data[secretKey], clientgoscheme.Scheme, externalClusterMapper)
+	if err != nil {
+		return 0, fmt.Errorf("failed to create external cluster client: %w", err)
+	}
+	defer closeClient()
+
+	// only the number of nodes is needed
+	newNodeList := func() *metav1.PartialObjectMetadataList {
+		list := &metav1.PartialObjectMetadataList{}
+		list.SetGroupVersionKind(corev1.SchemeGroupVersion.WithKind("NodeList"))
+		return list
+	}
+
+	nodes := 0
+	if err := agent.ListPages(ctx, externalClusterClient, newNodeList, a.opts.PageSize, func(list *metav1.PartialObjectMetadataList) error {
+		nodes += len(list.Items)
+		return nil
+	}); err != nil {
+		return 0, fmt.Errorf("failed listing nodes: %w", err)
+	}
+
+	return nodes, nil
+}
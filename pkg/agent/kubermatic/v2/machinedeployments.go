@@ -0,0 +1,150 @@
+/*
+Copyright 2023 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sort"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	v2types "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2/types"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	providerconfigtypes "github.com/kubermatic/machine-controller/pkg/providerconfig/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// flavorFields are the cloudProviderSpec fields naming the instance flavor, in
+// the order they are looked up.
+var flavorFields = []string{
+	"instanceType",   // AWS, Alibaba, Equinix Metal
+	"vmSize",         // Azure
+	"machineType",    // GCP
+	"size",           // DigitalOcean
+	"serverType",     // Hetzner
+	"flavor",         // OpenStack
+	"type",           // Linode
+	"plan",           // Vultr
+	"commercialType", // Scaleway
+}
+
+// collectMachineDeployments aggregates the MachineDeployments of the user cluster.
+func (a kubermaticAgent) collectMachineDeployments(ctx context.Context, userClusterClient client.Client) ([]v2types.MachineDeployment, error) {
+	var machineDeployments []clusterv1alpha1.MachineDeployment
+	newMachineDeploymentList := func() *clusterv1alpha1.MachineDeploymentList { return &clusterv1alpha1.MachineDeploymentList{} }
+	if err := agent.ListPages(ctx, userClusterClient, newMachineDeploymentList, a.opts.PageSize, func(list *clusterv1alpha1.MachineDeploymentList) error {
+		machineDeployments = append(machineDeployments, list.Items...)
+		return nil
+	}); err != nil {
+		return nil, fmt.Errorf("failed listing machine deployments: %w", err)
+	}
+
+	return aggregateMachineDeployments(machineDeployments), nil
+}
+
+// aggregateMachineDeployments groups MachineDeployments by operating system,
+// kubelet version and flavor.
+func aggregateMachineDeployments(machineDeployments []clusterv1alpha1.MachineDeployment) []v2types.MachineDeployment {
+	groups := map[v2types.MachineDeployment]*v2types.MachineDeployment{}
+
+	for _, md := range machineDeployments {
+		key := v2types.MachineDeployment{
+			KubeletVersion: md.Spec.Template.Spec.Versions.Kubelet,
+		}
+
+		// an unparsable provider spec still counts towards the replicas
+		config, err := providerconfigtypes.GetConfig(md.Spec.Template.Spec.ProviderSpec)
+		if err == nil {
+			key.OperatingSystem = string(config.OperatingSystem)
+			key.Flavor = flavorFromCloudProviderSpec(config.CloudProviderSpec.Raw)
+		}
+
+		group, ok := groups[key]
+		if !ok {
+			group = &v2types.MachineDeployment{
+				OperatingSystem: key.OperatingSystem,
+				KubeletVersion:  key.KubeletVersion,
+				Flavor:          key.Flavor,
+			}
+			groups[key] = group
+		}
+
+		group.Count++
+		// replicas default to 1
+		replicas := 1
+		if md.Spec.Replicas != nil {
+			replicas = int(*md.Spec.Replicas)
+		}
+		group.Replicas += replicas
+	}
+
+	result := make([]v2types.MachineDeployment, 0, len(groups))
+	for _, group := range groups {
+		result = append(result, *group)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		a, b := result[i], result[j]
+		if a.OperatingSystem != b.OperatingSystem {
+			return a.OperatingSystem < b.OperatingSystem
+		}
+		if a.KubeletVersion != b.KubeletVersion {
+			return a.KubeletVersion < b.KubeletVersion
+		}
+		return a.Flavor < b.Flavor
+	})
+
+	return result
+}
+
+// flavorFromCloudProviderSpec returns the flavor of a provider spec. Values
+// taken from a Secret or ConfigMap are ignored.
+func flavorFromCloudProviderSpec(raw []byte) string {
+	spec := map[string]json.RawMessage{}
+	if err := json.Unmarshal(raw, &spec); err != nil {
+		return ""
+	}
+
+	for _, field := range flavorFields {
+		value := providerconfigtypes.ConfigVarString{}
+		if err := json.Unmarshal(spec[field], &value); err == nil && value.Value != "" {
+			return value.Value
+		}
+	}
+
+	// vSphere, KubeVirt, Nutanix and others are sized by CPUs and memory
+	var sizing struct {
+		CPUs     int `json:"cpus"`
+		CPUCores int `json:"cpuCores"`
+		MemoryMB int `json:"memoryMB"`
+	}
+	if err := json.Unmarshal(raw, &sizing); err != nil {
+		return ""
+	}
+
+	cpus := sizing.CPUs
+	if cpus == 0 {
+		cpus = sizing.CPUCores
+	}
+	if cpus == 0 || sizing.MemoryMB == 0 {
+		return ""
+	}
+
+	return fmt.Sprintf("%d CPUs, %d MB", cpus, sizing.MemoryMB)
+}
@@ -0,0 +1,86 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"strings"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	v2types "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2/types"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	kubermaticv1helper "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1/helper"
+)
+
+// collectProjectMembers counts the users, service accounts and groups bound
+// to every project. Bindings to projects that are not part of the record
+// are ignored.
+func (a kubermaticAgent) collectProjectMembers(ctx context.Context, projects []v2types.Project, errs *[]v2types.CollectionError) {
+	byUUID := make(map[string]*v2types.Project, len(projects))
+	for i := range projects {
+		byUUID[projects[i].UUID] = &projects[i]
+	}
+
+	newUserBindingList := func() *kubermaticv1.UserProjectBindingList { return &kubermaticv1.UserProjectBindingList{} }
+	if err := agent.ListPages(ctx, a.Client, newUserBindingList, a.opts.PageSize, func(list *kubermaticv1.UserProjectBindingList) error {
+		for _, binding := range list.Items {
+			project, ok := byUUID[a.anonymizer.UUID(binding.Spec.ProjectID)]
+			if !ok {
+				continue
+			}
+
+			if kubermaticv1helper.IsProjectServiceAccount(binding.Spec.UserEmail) {
+				project.ServiceAccounts++
+				continue
+			}
+
+			if project.Members == nil {
+				project.Members = map[string]int{}
+			}
+			project.Members[bindingRole(binding.Spec.Group, binding.Spec.ProjectID)]++
+		}
+		return nil
+	}); err != nil {
+		a.addError(errs, "", "userprojectbindings", "", err)
+	}
+
+	newGroupBindingList := func() *kubermaticv1.GroupProjectBindingList { return &kubermaticv1.GroupProjectBindingList{} }
+	if err := agent.ListPages(ctx, a.Client, newGroupBindingList, a.opts.PageSize, func(list *kubermaticv1.GroupProjectBindingList) error {
+		for _, binding := range list.Items {
+			project, ok := byUUID[a.anonymizer.UUID(binding.Spec.ProjectID)]
+			if !ok {
+				continue
+			}
+
+			if project.GroupBindings == nil {
+				project.GroupBindings = map[string]int{}
+			}
+			project.GroupBindings[bindingRole(binding.Spec.Role, binding.Spec.ProjectID)]++
+		}
+		return nil
+	}); err != nil {
+		a.addError(errs, "", "groupprojectbindings", "", err)
+	}
+}
+
+// bindingRole returns the role of a project binding. KKP names the groups
+// of a project <role>-<project ID>, e.g. owners-abcd1234, which would
+// reveal the project.
+func bindingRole(group, projectID string) string {
+	return strings.TrimSuffix(group, "-"+projectID)
+}
@@ -0,0 +1,185 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"net/url"
+	"sort"
+	"strings"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+// providerLocation tells where a provider places the machines of a cluster.
+// Any of the functions can be nil if the provider has no such location.
+type providerLocation struct {
+	// region returns the region of a datacenter as named by the provider.
+	region func(spec *kubermaticv1.DatacenterSpec) string
+	// datacenterSubLocation returns a customer-specific location within the
+	// datacenter, e.g. the vSphere cluster. It is anonymized when reported.
+	datacenterSubLocation func(spec *kubermaticv1.DatacenterSpec) string
+	// clusterSubLocation returns a customer-specific location of a user
+	// cluster, e.g. the Nutanix cluster. It is anonymized when reported.
+	clusterSubLocation func(cloud kubermaticv1.CloudSpec) string
+}
+
+// providerLocations must cover every provider of the KKP API, which is
+// checked by TestUnmappedProviders.
+var providerLocations = map[kubermaticv1.ProviderType]providerLocation{
+	// external clusters are not placed in KKP datacenters
+	kubermaticv1.AKSCloudProvider: {},
+	kubermaticv1.EKSCloudProvider: {},
+	kubermaticv1.GKECloudProvider: {},
+
+	// machines are provided by the user, wherever they are
+	kubermaticv1.BringYourOwnCloudProvider: {},
+	kubermaticv1.EdgeCloudProvider:         {},
+	kubermaticv1.FakeCloudProvider:         {},
+
+	kubermaticv1.AlibabaCloudProvider: {
+		region: func(spec *kubermaticv1.DatacenterSpec) string { return spec.Alibaba.Region },
+	},
+	kubermaticv1.AnexiaCloudProvider: {
+		region: func(spec *kubermaticv1.DatacenterSpec) string { return spec.Anexia.LocationID },
+	},
+	kubermaticv1.AWSCloudProvider: {
+		region: func(spec *kubermaticv1.DatacenterSpec) string { return spec.AWS.Region },
+	},
+	kubermaticv1.AzureCloudProvider: {
+		region: func(spec *kubermaticv1.DatacenterSpec) string { return spec.Azure.Location },
+	},
+	kubermaticv1.DigitaloceanCloudProvider: {
+		region: func(spec *kubermaticv1.DatacenterSpec) string { return spec.Digitalocean.Region },
+	},
+	kubermaticv1.GCPCloudProvider: {
+		region: func(spec *kubermaticv1.DatacenterSpec) string { return spec.GCP.Region },
+	},
+	kubermaticv1.HetznerCloudProvider: {
+		region: func(spec *kubermaticv1.DatacenterSpec) string {
+			// the location is optional, the datacenter is e.g. fsn1-dc14
+			if spec.Hetzner.Location != "" {
+				return spec.Hetzner.Location
+			}
+			location, _, _ := strings.Cut(spec.Hetzner.Datacenter, "-")
+			return location
+		},
+	},
+	kubermaticv1.KubevirtCloudProvider: {
+		// VMs run in the infra cluster, which is only known by its kubeconfig
+	},
+	kubermaticv1.NutanixCloudProvider: {
+		datacenterSubLocation: func(spec *kubermaticv1.DatacenterSpec) string { return spec.Nutanix.Endpoint },
+		clusterSubLocation: func(cloud kubermaticv1.CloudSpec) string {
+			if cloud.Nutanix == nil {
+				return ""
+			}
+			return cloud.Nutanix.ClusterName
+		},
+	},
+	kubermaticv1.OpenstackCloudProvider: {
+		region:                func(spec *kubermaticv1.DatacenterSpec) string { return spec.Openstack.Region },
+		datacenterSubLocation: func(spec *kubermaticv1.DatacenterSpec) string { return spec.Openstack.AvailabilityZone },
+	},
+	kubermaticv1.PacketCloudProvider: {
+		region: func(spec *kubermaticv1.DatacenterSpec) string {
+			// metros replaced facilities, which are kept for older datacenters
+			if spec.Packet.Metro != "" {
+				return spec.Packet.Metro
+			}
+			facilities := append([]string{}, spec.Packet.Facilities...)
+			sort.Strings(facilities)
+			return strings.Join(facilities, ",")
+		},
+	},
+	kubermaticv1.VMwareCloudDirectorCloudProvider: {
+		datacenterSubLocation: func(spec *kubermaticv1.DatacenterSpec) string {
+			return urlHost(spec.VMwareCloudDirector.URL)
+		},
+		clusterSubLocation: func(cloud kubermaticv1.CloudSpec) string {
+			if cloud.VMwareCloudDirector == nil || cloud.VMwareCloudDirector.VDC == "" {
+				return ""
+			}
+			return cloud.VMwareCloudDirector.Organization + "/" + cloud.VMwareCloudDirector.VDC
+		},
+	},
+	kubermaticv1.VSphereCloudProvider: {
+		region:                func(spec *kubermaticv1.DatacenterSpec) string { return spec.VSphere.Datacenter },
+		datacenterSubLocation: func(spec *kubermaticv1.DatacenterSpec) string { return spec.VSphere.Cluster },
+	},
+}
+
+// UnmappedProviders returns the providers of the KKP API that have no entry
+// in the provider registry, so that their locations would not be reported.
+func UnmappedProviders() []kubermaticv1.ProviderType {
+	var unmapped []kubermaticv1.ProviderType
+	for _, provider := range kubermaticv1.SupportedProviders {
+		if _, ok := providerLocations[provider]; !ok {
+			unmapped = append(unmapped, provider)
+		}
+	}
+
+	return unmapped
+}
+
+func datacenterCloudRegionName(spec *kubermaticv1.DatacenterSpec, providerName string) string {
+	location := providerLocations[kubermaticv1.ProviderType(providerName)]
+	if spec == nil || location.region == nil {
+		return ""
+	}
+
+	return location.region(spec)
+}
+
+func datacenterSubLocation(spec *kubermaticv1.DatacenterSpec, providerName string) string {
+	location := providerLocations[kubermaticv1.ProviderType(providerName)]
+	if spec == nil || location.datacenterSubLocation == nil {
+		return ""
+	}
+
+	return location.datacenterSubLocation(spec)
+}
+
+func clusterSubLocation(cloud kubermaticv1.CloudSpec, providerName string) string {
+	location := providerLocations[kubermaticv1.ProviderType(providerName)]
+	if location.clusterSubLocation == nil {
+		return ""
+	}
+
+	return location.clusterSubLocation(cloud)
+}
+
+// anonymizeOptional returns the UUID of a non-empty value.
+func anonymizeOptional(anonymizer agent.Anonymizer, value string) string {
+	if value == "" {
+		return ""
+	}
+
+	return anonymizer.UUID(value)
+}
+
+// urlHost returns the host of an endpoint URL, or the value as is if it
+// is no URL.
+func urlHost(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil || u.Host == "" {
+		return endpoint
+	}
+
+	return u.Hostname()
+}
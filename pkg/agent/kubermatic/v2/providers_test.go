@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"testing"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+)
+
+func TestUnmappedProviders(t *testing.T) {
+	if unmapped := UnmappedProviders(); len(unmapped) > 0 {
+		t.Errorf("providers without a location mapping: %v", unmapped)
+	}
+}
+
+func TestDatacenterCloudRegionName(t *testing.T) {
+	testCases := map[string]struct {
+		provider kubermaticv1.ProviderType
+		spec     kubermaticv1.DatacenterSpec
+		expected string
+	}{
+		"aws": {
+			provider: kubermaticv1.AWSCloudProvider,
+			spec:     kubermaticv1.DatacenterSpec{AWS: &kubermaticv1.DatacenterSpecAWS{Region: "eu-central-1"}},
+			expected: "eu-central-1",
+		},
+		"packet metro": {
+			provider: kubermaticv1.PacketCloudProvider,
+			spec:     kubermaticv1.DatacenterSpec{Packet: &kubermaticv1.DatacenterSpecPacket{Metro: "am", Facilities: []string{"ams1"}}},
+			expected: "am",
+		},
+		"packet facilities": {
+			provider: kubermaticv1.PacketCloudProvider,
+			spec:     kubermaticv1.DatacenterSpec{Packet: &kubermaticv1.DatacenterSpecPacket{Facilities: []string{"fra2", "ams1"}}},
+			expected: "ams1,fra2",
+		},
+		"hetzner location": {
+			provider: kubermaticv1.HetznerCloudProvider,
+			spec:     kubermaticv1.DatacenterSpec{Hetzner: &kubermaticv1.DatacenterSpecHetzner{Location: "nbg1", Datacenter: "fsn1-dc14"}},
+			expected: "nbg1",
+		},
+		"hetzner datacenter": {
+			provider: kubermaticv1.HetznerCloudProvider,
+			spec:     kubermaticv1.DatacenterSpec{Hetzner: &kubermaticv1.DatacenterSpecHetzner{Datacenter: "fsn1-dc14"}},
+			expected: "fsn1",
+		},
+		"kubevirt has no region": {
+			provider: kubermaticv1.KubevirtCloudProvider,
+			spec:     kubermaticv1.DatacenterSpec{Kubevirt: &kubermaticv1.DatacenterSpecKubevirt{}},
+			expected: "",
+		},
+		"unknown provider": {
+			provider: "unknown",
+			expected: "",
+		},
+	}
+
+	for name, tc := range testCases {
+		t.Run(name, func(t *testing.T) {
+			if region := datacenterCloudRegionName(&tc.spec, string(tc.provider)); region != tc.expected {
+				t.Errorf("expected region %q, got %q", tc.expected, region)
+			}
+		})
+	}
+}
+
+func TestSubLocations(t *testing.T) {
+	vsphere := kubermaticv1.DatacenterSpec{VSphere: &kubermaticv1.DatacenterSpecVSphere{Datacenter: "dc-1", Cluster: "cluster-a"}}
+	if location := datacenterSubLocation(&vsphere, string(kubermaticv1.VSphereCloudProvider)); location != "cluster-a" {
+		t.Errorf("expected the vSphere cluster, got %q", location)
+	}
+
+	vcd := kubermaticv1.DatacenterSpec{VMwareCloudDirector: &kubermaticv1.DatacenterSpecVMwareCloudDirector{URL: "https://vcd.example.com/api"}}
+	if location := datacenterSubLocation(&vcd, string(kubermaticv1.VMwareCloudDirectorCloudProvider)); location != "vcd.example.com" {
+		t.Errorf("expected the VMware Cloud Director host, got %q", location)
+	}
+
+	nutanix := kubermaticv1.CloudSpec{Nutanix: &kubermaticv1.NutanixCloudSpec{ClusterName: "prism-1"}}
+	if location := clusterSubLocation(nutanix, string(kubermaticv1.NutanixCloudProvider)); location != "prism-1" {
+		t.Errorf("expected the Nutanix cluster, got %q", location)
+	}
+}
@@ -0,0 +1,123 @@
+/*
+Copyright 2023 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"sync"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	v2types "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2/types"
+
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/provider"
+)
+
+// seedResult is everything collected from a single seed.
+type seedResult struct {
+	// seed is nil if the seed could not be described.
+	seed     *v2types.Seed
+	clusters []v2types.Cluster
+	errors   []v2types.CollectionError
+}
+
+// collectSeeds collects all seeds with at most SeedWorkers at a time. The
+// results are ordered by seed name, so that the record does not depend on
+// which seed answered first.
+func (a kubermaticAgent) collectSeeds(ctx context.Context, seeds []kubermaticv1.Seed, seedClientGetter provider.SeedClientGetter, defaultExposeStrategy kubermaticv1.ExposeStrategy) []seedResult {
+	seeds = append([]kubermaticv1.Seed{}, seeds...)
+	sort.Slice(seeds, func(i, j int) bool {
+		return seeds[i].Name < seeds[j].Name
+	})
+
+	results := make([]seedResult, len(seeds))
+	workers := make(chan struct{}, a.opts.SeedWorkers)
+
+	var wg sync.WaitGroup
+	for i := range seeds {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			workers <- struct{}{}
+			defer func() { <-workers }()
+
+			results[i] = a.collectSeed(ctx, &seeds[i], seedClientGetter, defaultExposeStrategy)
+		}(i)
+	}
+	wg.Wait()
+
+	return results
+}
+
+// collectSeed describes the seed and lists its clusters within the
+// SeedTimeout. The user clusters are only visited once all clusters are
+// listed and with a budget of their own, so that unreachable user clusters
+// cannot cost the records of the clusters listed after them.
+func (a kubermaticAgent) collectSeed(ctx context.Context, kSeed *kubermaticv1.Seed, seedClientGetter provider.SeedClientGetter, defaultExposeStrategy kubermaticv1.ExposeStrategy) seedResult {
+	listCtx, cancel := context.WithTimeout(ctx, a.opts.SeedTimeout)
+	defer cancel()
+
+	result := seedResult{}
+	seedUUID := a.anonymizer.UUID(kSeed.Name)
+
+	// the seed itself is described by the master cluster, so report it
+	// even if it cannot be reached
+	seed, err := seedFromKube(a.anonymizer, *kSeed, defaultExposeStrategy)
+	if err != nil {
+		a.addError(&result.errors, seedUUID, "seeds", seedUUID, err)
+	} else {
+		result.seed = &seed
+	}
+
+	seedClient, err := seedClientGetter(kSeed)
+	if err != nil {
+		a.addError(&result.errors, seedUUID, "seeds", seedUUID, fmt.Errorf("failed getting seed client: %w", err))
+		return result
+	}
+
+	// the cluster namespace of every listed cluster, to visit it later
+	namespaces := []string{}
+
+	//  List clusters per seed, the API server returns them ordered by name
+	newClusterList := func() *kubermaticv1.ClusterList { return &kubermaticv1.ClusterList{} }
+	if err := agent.ListPages(listCtx, seedClient, newClusterList, a.opts.PageSize, func(clusterList *kubermaticv1.ClusterList) error {
+		for _, kCluster := range clusterList.Items {
+			clusterUUID := a.anonymizer.UUID(kCluster.Name)
+
+			cluster, err := clusterFromKube(a.anonymizer, kCluster, kSeed.Name)
+			if err != nil {
+				a.addError(&result.errors, seedUUID, "clusters", clusterUUID, err)
+				continue
+			}
+
+			result.clusters = append(result.clusters, cluster)
+			namespaces = append(namespaces, kCluster.Status.NamespaceName)
+		}
+		return nil
+	}); err != nil {
+		a.addError(&result.errors, seedUUID, "clusters", "", err)
+	}
+
+	a.collectUserClusters(ctx, seedClient, result.clusters, namespaces, &result.errors, seedUUID)
+
+	a.log.Infow("Collected userclusters", "seed", kSeed.Name, "clusters", len(result.clusters))
+
+	return result
+}
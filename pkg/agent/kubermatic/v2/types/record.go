@@ -31,16 +31,43 @@ type Record struct {
 	KubermaticEdition string `json:"kubermatic_edition"`
 	// KubermaticVersion is the Kubermatic Release Version.
 	KubermaticVersion string `json:"kubermatic_version"`
+	// Configuration are the installation-wide defaults of the KubermaticConfiguration.
+	Configuration Configuration `json:"configuration"`
 	// Seeds is a list of seed-specific information.
 	Seeds []Seed `json:"seeds,omitempty"`
 	// Clusters is a list of cluster-specific information.
 	Clusters []Cluster `json:"clusters,omitempty"`
+	// ExternalClusters is a list of clusters imported into or provisioned by
+	// KKP, e.g. EKS, AKS, GKE or KubeOne clusters.
+	ExternalClusters []ExternalCluster `json:"external_clusters,omitempty"`
 	// Users is a list of users
 	Users []User `json:"users,omitempty"`
 	// Projects is a list of projects
 	Projects []Project `json:"projects,omitempty"`
 	// SSHKeys is a list of SSHKeys
 	SSHKeys []SSHKey `json:"ssh_keys,omitempty"`
+	// ApplicationAdoption counts the clusters per installed application and version.
+	ApplicationAdoption []ApplicationAdoption `json:"application_adoption,omitempty"`
+	// AddonAdoption counts the clusters per installed addon.
+	AddonAdoption []AddonAdoption `json:"addon_adoption,omitempty"`
+	// Errors lists everything that could not be collected, so that missing
+	// data can be told apart from data that does not exist.
+	Errors []CollectionError `json:"errors,omitempty"`
+}
+
+// CollectionError describes a resource that could not be collected. The
+// error message is only logged by the agent, as it may contain names,
+// namespaces, hostnames or addresses.
+type CollectionError struct {
+	// SeedUUID is the seed the error occurred on, empty for the master cluster.
+	SeedUUID string `json:"seed_uuid,omitempty"`
+	// Resource is the resource that failed, e.g. clusters.
+	Resource string `json:"resource"`
+	// ObjectUUID is the object that failed, empty if the resource could not
+	// be listed at all.
+	ObjectUUID string `json:"object_uuid,omitempty"`
+	// Reason is the Kubernetes status reason, e.g. Forbidden or Timeout, if known.
+	Reason string `json:"reason,omitempty"`
 }
 
 func (r *Record) String() string {
@@ -60,6 +87,30 @@ type Seed struct {
 	// Each DC must have a globally unique identifier (i.e. names must be unique
 	// across all seeds).
 	Datacenters []Datacenter `json:"datacenters,omitempty"`
+	// MLAEnabled is true if user cluster MLA can be enabled on this seed.
+	MLAEnabled bool `json:"mla_enabled"`
+	// EtcdBackupRestoreEnabled is true if etcd backup and restore destinations
+	// are configured for this seed.
+	EtcdBackupRestoreEnabled bool `json:"etcd_backup_restore_enabled"`
+	// EtcdBackupDestinations is the number of configured backup destinations.
+	EtcdBackupDestinations int `json:"etcd_backup_destinations,omitempty"`
+	// MeteringEnabled is true if metering reports are generated on this seed.
+	MeteringEnabled bool `json:"metering_enabled"`
+	// ProxyEnabled is true if an HTTP proxy is configured for this seed.
+	ProxyEnabled bool `json:"proxy_enabled"`
+	// NodePortProxyEnabled is true unless the nodeport-proxy is disabled on this seed.
+	NodePortProxyEnabled bool `json:"nodeport_proxy_enabled"`
+}
+
+// Configuration are the defaults that apply to all seeds and clusters unless
+// overridden.
+type Configuration struct {
+	// ExposeStrategy is the default expose strategy of all seeds.
+	ExposeStrategy string `json:"expose_strategy,omitempty"`
+	// ProxyEnabled is true if an HTTP proxy is configured for KKP components.
+	ProxyEnabled bool `json:"proxy_enabled"`
+	// FeatureGates are the configured KKP feature gates and whether they are enabled.
+	FeatureGates map[string]bool `json:"feature_gates,omitempty"`
 }
 
 // Datacenter specifies the data for a datacenter.
@@ -74,6 +125,9 @@ type Datacenter struct {
 	Provider string `json:"provider,omitempty"`
 	// Region contains cloud provider region for this datacenter.
 	Region string `json:"region,omitempty"`
+	// SubLocationUUID identifies a customer-specific location within the
+	// datacenter, e.g. the vSphere cluster or the Nutanix endpoint.
+	SubLocationUUID string `json:"sub_location_uuid,omitempty"`
 }
 
 type Cluster struct {
@@ -110,6 +164,118 @@ type Cluster struct {
 
 	// EnableUserSSHKeyAgent control whether the UserSSHKeyAgent will be deployed in the user cluster or not.
 	UserSSHKeyAgentEnabled bool `json:"user_ssh_key_agent_enabled"`
+
+	// Phase is the lifecycle phase, e.g. Creating, Running or Terminating.
+	Phase string `json:"phase,omitempty"`
+
+	// Health is the state of the control plane and cluster components.
+	Health ClusterHealth `json:"health"`
+
+	// AgeBucket is the coarse age of the cluster, e.g. 7d-30d.
+	AgeBucket string `json:"age_bucket,omitempty"`
+
+	// Deleting is true if the cluster has a deletion timestamp.
+	Deleting bool `json:"deleting"`
+
+	// UpdatePending is true if the control plane does not run the desired version yet.
+	UpdatePending bool `json:"update_pending"`
+
+	// MachineDeployments aggregates the MachineDeployments of the user cluster,
+	// it is empty if the user cluster could not be reached.
+	MachineDeployments []MachineDeployment `json:"machine_deployments,omitempty"`
+
+	// Applications are the applications installed in the user cluster, it is
+	// empty if the user cluster could not be reached.
+	Applications []Application `json:"applications,omitempty"`
+
+	// Addons are the addons installed in the cluster.
+	Addons []Addon `json:"addons,omitempty"`
+}
+
+// Application is an application installed from the KKP application catalogue.
+type Application struct {
+	Name    string `json:"name"`
+	Version string `json:"version,omitempty"`
+}
+
+// Addon is a KKP addon installed in a cluster.
+type Addon struct {
+	Name string `json:"name"`
+	// IsDefault is true if the addon is installed into every new cluster.
+	IsDefault bool `json:"is_default"`
+}
+
+// ApplicationAdoption is the number of clusters an application version is installed in.
+type ApplicationAdoption struct {
+	Name     string `json:"name"`
+	Version  string `json:"version,omitempty"`
+	Clusters int    `json:"clusters"`
+}
+
+// AddonAdoption is the number of clusters an addon is installed in.
+type AddonAdoption struct {
+	Name     string `json:"name"`
+	Clusters int    `json:"clusters"`
+}
+
+// ClusterHealth is the state of every cluster component, one of up, down or
+// provisioning. Optional components that are not deployed are empty.
+type ClusterHealth struct {
+	Apiserver                    string `json:"apiserver,omitempty"`
+	Scheduler                    string `json:"scheduler,omitempty"`
+	Controller                   string `json:"controller,omitempty"`
+	MachineController            string `json:"machine_controller,omitempty"`
+	Etcd                         string `json:"etcd,omitempty"`
+	OpenVPN                      string `json:"openvpn,omitempty"`
+	Konnectivity                 string `json:"konnectivity,omitempty"`
+	CloudProviderInfrastructure  string `json:"cloud_provider_infrastructure,omitempty"`
+	UserClusterControllerManager string `json:"user_cluster_controller_manager,omitempty"`
+	ApplicationController        string `json:"application_controller,omitempty"`
+	GatekeeperController         string `json:"gatekeeper_controller,omitempty"`
+	GatekeeperAudit              string `json:"gatekeeper_audit,omitempty"`
+	Monitoring                   string `json:"monitoring,omitempty"`
+	Logging                      string `json:"logging,omitempty"`
+	AlertmanagerConfig           string `json:"alertmanager_config,omitempty"`
+	MLAGateway                   string `json:"mla_gateway,omitempty"`
+	OperatingSystemManager       string `json:"operating_system_manager,omitempty"`
+	KubernetesDashboard          string `json:"kubernetes_dashboard,omitempty"`
+}
+
+// MachineDeployment aggregates all MachineDeployments of a cluster that share
+// the same operating system, kubelet version and flavor.
+type MachineDeployment struct {
+	OperatingSystem string `json:"operating_system,omitempty"`
+	KubeletVersion  string `json:"kubelet_version,omitempty"`
+	// Flavor is the instance type, size or flavor of the machines as named by
+	// the cloud provider, or their CPUs and memory if the provider has none.
+	Flavor string `json:"flavor,omitempty"`
+	// Count is the number of MachineDeployments.
+	Count int `json:"count"`
+	// Replicas is the total number of desired replicas.
+	Replicas int `json:"replicas"`
+}
+
+// ExternalCluster is a cluster that is managed, but not run by KKP.
+type ExternalCluster struct {
+	UUID string `json:"uuid,omitempty"`
+
+	// ProjectUUID helps to uniquely relate this cluster with the owned project
+	ProjectUUID string `json:"project_uuid,omitempty"`
+
+	// Provider is the provider type, one of aks, eks, gke, kubeone or bringyourown.
+	Provider string `json:"provider,omitempty"`
+
+	// KubeOneProvider is the cloud provider KubeOne clusters run on.
+	KubeOneProvider string `json:"kubeone_provider,omitempty"`
+
+	KubernetesVersion string `json:"kubernetes_version,omitempty"`
+
+	// Phase is the state of the cluster as seen by KKP, e.g. Running or Error.
+	Phase string `json:"phase,omitempty"`
+
+	// NodeCount is the number of nodes, it is not set if the cluster could
+	// not be reached.
+	NodeCount *int `json:"node_count,omitempty"`
 }
 
 // ClusterNetworkingConfig specifies the different networking
@@ -138,6 +304,10 @@ type CNIPluginSettings struct {
 type Cloud struct {
 	ProviderName   string `json:"provider_name,omitempty"`
 	DatacenterUUID string `json:"datacenter_uuid,omitempty"`
+	// SubLocationUUID identifies a customer-specific location of the cluster
+	// within its datacenter, e.g. the Nutanix cluster or the VMware Cloud
+	// Director VDC.
+	SubLocationUUID string `json:"sub_location_uuid,omitempty"`
 }
 
 type MLASettings struct {
@@ -149,12 +319,21 @@ type MLASettings struct {
 
 type Project struct {
 	UUID string `json:"uuid,omitempty"`
+	// Members counts the users bound to the project by role, e.g. owners or
+	// editors. Service accounts are not included.
+	Members map[string]int `json:"members,omitempty"`
+	// ServiceAccounts is the number of service accounts bound to the project.
+	ServiceAccounts int `json:"service_accounts"`
+	// GroupBindings counts the groups bound to the project by role.
+	GroupBindings map[string]int `json:"group_bindings,omitempty"`
 }
 
 type User struct {
 	UUID string `json:"uuid,omitempty"`
 	// IsAdmin indicates admin role
 	IsAdmin bool `json:"is_admin"`
+	// IsServiceAccount is true for the users backing project service accounts.
+	IsServiceAccount bool `json:"is_service_account"`
 }
 
 type SSHKey struct {
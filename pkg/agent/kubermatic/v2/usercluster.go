@@ -0,0 +1,196 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	v2types "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2/types"
+
+	clusterv1alpha1 "github.com/kubermatic/machine-controller/pkg/apis/cluster/v1alpha1"
+	appskubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/apps.kubermatic/v1"
+	"k8c.io/kubermatic/v2/pkg/resources"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// userClusterTimeout bounds the time spent on a single user cluster, so that
+// unreachable clusters do not stall the whole collection.
+const userClusterTimeout = 30 * time.Second
+
+var userClusterScheme = runtime.NewScheme()
+
+// userClusterMapper maps the only kinds read from user clusters, so that
+// no discovery is needed for every single cluster.
+var userClusterMapper = newStaticRESTMapper(meta.RESTScopeNamespace,
+	clusterv1alpha1.SchemeGroupVersion.WithKind("MachineDeployment"),
+	appskubermaticv1.SchemeGroupVersion.WithKind("ApplicationInstallation"),
+)
+
+func init() {
+	utilruntime.Must(clusterv1alpha1.AddToScheme(userClusterScheme))
+	utilruntime.Must(appskubermaticv1.AddToScheme(userClusterScheme))
+}
+
+// collectUserClusters visits the user clusters of a seed with at most
+// UserClusterWorkers at a time within the UserClusterTimeout. The clusters
+// and their namespaces are in the same order. Clusters that are not visited
+// in time are still reported, only without their details.
+func (a kubermaticAgent) collectUserClusters(ctx context.Context, seedClient client.Client, clusters []v2types.Cluster, namespaces []string, errs *[]v2types.CollectionError, seedUUID string) {
+	ctx, cancel := context.WithTimeout(ctx, a.opts.UserClusterTimeout)
+	defer cancel()
+
+	// every cluster has its own errors, so that they are reported in the
+	// order of the clusters
+	clusterErrs := make([][]v2types.CollectionError, len(clusters))
+	workers := make(chan struct{}, a.opts.UserClusterWorkers)
+
+	var wg sync.WaitGroup
+	for i := range clusters {
+		workers <- struct{}{}
+
+		if err := ctx.Err(); err != nil {
+			<-workers
+			a.addError(&clusterErrs[i], seedUUID, "userclusters", "", fmt.Errorf("%d user clusters not visited: %w", len(clusters)-i, err))
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-workers }()
+
+			a.collectUserCluster(ctx, seedClient, namespaces[i], &clusters[i], &clusterErrs[i], seedUUID)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, clusterErr := range clusterErrs {
+		*errs = append(*errs, clusterErr...)
+	}
+}
+
+// collectUserCluster adds everything that is found in the cluster namespace
+// on the seed and in the user cluster itself. User clusters might be paused,
+// still being created or unreachable, which must not prevent reporting
+// everything else, so failures are only recorded.
+func (a kubermaticAgent) collectUserCluster(ctx context.Context, seedClient client.Client, namespace string, cluster *v2types.Cluster, errs *[]v2types.CollectionError, seedUUID string) {
+	// the cluster is still being created
+	if namespace == "" {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, userClusterTimeout)
+	defer cancel()
+
+	var err error
+	cluster.Addons, err = a.collectAddons(ctx, seedClient, namespace)
+	if err != nil {
+		a.addError(errs, seedUUID, "addons", cluster.UUID, err)
+	}
+
+	userClusterClient, closeClient, err := newUserClusterClient(ctx, seedClient, namespace)
+	if err != nil {
+		a.addError(errs, seedUUID, "userclusters", cluster.UUID, err)
+		return
+	}
+	defer closeClient()
+
+	cluster.MachineDeployments, err = a.collectMachineDeployments(ctx, userClusterClient)
+	if err != nil {
+		a.addError(errs, seedUUID, "machinedeployments", cluster.UUID, err)
+	}
+
+	cluster.Applications, err = a.collectApplications(ctx, userClusterClient)
+	if err != nil {
+		a.addError(errs, seedUUID, "applicationinstallations", cluster.UUID, err)
+	}
+}
+
+// newUserClusterClient connects to the user cluster via its admin kubeconfig
+// in the cluster namespace on the seed. The returned function releases the
+// connections to the cluster.
+func newUserClusterClient(ctx context.Context, seedClient client.Client, namespace string) (client.Client, func(), error) {
+	secret := &corev1.Secret{}
+	key := client.ObjectKey{Namespace: namespace, Name: resources.AdminKubeconfigSecretName}
+	if err := seedClient.Get(ctx, key, secret); err != nil {
+		return nil, nil, fmt.Errorf("failed to get admin kubeconfig: %w", err)
+	}
+
+	userClusterClient, closeClient, err := newClusterClient(secret.Data[resources.KubeconfigSecretKey], userClusterScheme, userClusterMapper)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to create user cluster client: %w", err)
+	}
+
+	return userClusterClient, closeClient, nil
+}
+
+// newClusterClient creates a client for a cluster that is only visited
+// once per collection, like a user or external cluster. On large fleets
+// neither discovery nor a transport that outlives the collection can be
+// afforded for each of them, so the client uses a static RESTMapper and the
+// returned function closes its connections.
+func newClusterClient(kubeconfig []byte, scheme *runtime.Scheme, mapper meta.RESTMapper) (client.Client, func(), error) {
+	cfg, err := clientcmd.RESTConfigFromKubeConfig(kubeconfig)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid kubeconfig: %w", err)
+	}
+	cfg.Timeout = userClusterTimeout
+
+	// client-go keeps transports in a global cache for the lifetime of the
+	// process, unless they use a custom proxy function
+	if cfg.Proxy == nil {
+		cfg.Proxy = http.ProxyFromEnvironment
+	}
+
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	c, err := client.New(cfg, client.Options{
+		Scheme:     scheme,
+		Mapper:     mapper,
+		HTTPClient: httpClient,
+	})
+	if err != nil {
+		httpClient.CloseIdleConnections()
+		return nil, nil, err
+	}
+
+	return c, httpClient.CloseIdleConnections, nil
+}
+
+// newStaticRESTMapper returns a RESTMapper that only knows the given kinds.
+func newStaticRESTMapper(scope meta.RESTScope, gvks ...schema.GroupVersionKind) meta.RESTMapper {
+	mapper := meta.NewDefaultRESTMapper(nil)
+	for _, gvk := range gvks {
+		mapper.Add(gvk, scope)
+	}
+
+	return mapper
+}
@@ -41,15 +41,17 @@ type serverVersionInfo interface {
 type kubernetesAgent struct {
 	client.Client
 	serverVersionInfo
-	dataStore datastore.DataStore
-	log       *zap.SugaredLogger
+	dataStore  datastore.DataStore
+	anonymizer agent.Anonymizer
+	log        *zap.SugaredLogger
 }
 
-func NewAgent(client client.Client, info serverVersionInfo, dataStore datastore.DataStore, log *zap.SugaredLogger) agent.Agent {
+func NewAgent(client client.Client, info serverVersionInfo, dataStore datastore.DataStore, anonymizer agent.Anonymizer, log *zap.SugaredLogger) agent.Agent {
 	return kubernetesAgent{
 		Client:            client,
 		serverVersionInfo: info,
 		dataStore:         dataStore,
+		anonymizer:        anonymizer,
 		log:               log,
 	}
 }
@@ -64,7 +66,7 @@ func (a kubernetesAgent) Collect(ctx context.Context) error {
 
 	record := v1types.Record{
 		KindVersion: agent.KindVersion{
-			Kind:    "kubernetes",
+			Kind:    agent.KindKubernetes,
 			Version: telemetryversion.V1Version,
 		},
 		Time:              time.Now().UTC(),
@@ -76,7 +78,7 @@ func (a kubernetesAgent) Collect(ctx context.Context) error {
 		return err
 	}
 	for _, knode := range knodes.Items {
-		node, err := nodeFromKubeNode(knode)
+		node, err := nodeFromKubeNode(a.anonymizer, knode)
 		if err != nil {
 			return err
 		}
@@ -92,13 +94,9 @@ func (a kubernetesAgent) Collect(ctx context.Context) error {
 	return a.dataStore.Store(ctx, data)
 }
 
-func nodeFromKubeNode(kn corev1.Node) (v1types.Node, error) {
-	id, err := getID(kn)
-	if err != nil {
-		return v1types.Node{}, err
-	}
+func nodeFromKubeNode(anonymizer agent.Anonymizer, kn corev1.Node) (v1types.Node, error) {
 	n := v1types.Node{
-		ID:                      id,
+		ID:                      getID(anonymizer, kn),
 		OperatingSystem:         agent.StrPtr(kn.Status.NodeInfo.OperatingSystem),
 		OSImage:                 agent.StrPtr(kn.Status.NodeInfo.OSImage),
 		KernelVersion:           agent.StrPtr(kn.Status.NodeInfo.KernelVersion),
@@ -123,10 +121,10 @@ func nodeFromKubeNode(kn corev1.Node) (v1types.Node, error) {
 	return n, nil
 }
 
-func getID(kn corev1.Node) (string, error) {
+func getID(anonymizer agent.Anonymizer, kn corev1.Node) string {
 	// We don't want to report the node's Name - that is Personally Identifiable Information.
 	// The MachineID is apparently not always populated and SystemUUID is ill-defined. Let's
 	// just hash them all together. It should be stable, and this reduces risk
 	// of PII leakage.
-	return agent.HashOf(kn.Name + kn.Status.NodeInfo.MachineID + kn.Status.NodeInfo.SystemUUID)
+	return anonymizer.ID(kn.Name + kn.Status.NodeInfo.MachineID + kn.Status.NodeInfo.SystemUUID)
 }
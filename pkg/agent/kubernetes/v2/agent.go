@@ -41,15 +41,17 @@ type serverVersionInfo interface {
 type kubernetesAgent struct {
 	client.Client
 	serverVersionInfo
-	dataStore datastore.DataStore
-	log       *zap.SugaredLogger
+	dataStore  datastore.DataStore
+	anonymizer agent.Anonymizer
+	log        *zap.SugaredLogger
 }
 
-func NewAgent(client client.Client, info serverVersionInfo, dataStore datastore.DataStore, log *zap.SugaredLogger) agent.Agent {
+func NewAgent(client client.Client, info serverVersionInfo, dataStore datastore.DataStore, anonymizer agent.Anonymizer, log *zap.SugaredLogger) agent.Agent {
 	return kubernetesAgent{
 		Client:            client,
 		serverVersionInfo: info,
 		dataStore:         dataStore,
+		anonymizer:        anonymizer,
 		log:               log,
 	}
 }
@@ -64,24 +66,25 @@ func (a kubernetesAgent) Collect(ctx context.Context) error {
 
 	record := v2types.Record{
 		KindVersion: agent.KindVersion{
-			Kind:    "kubernetes",
+			Kind:    agent.KindKubernetes,
 			Version: telemetryversion.V2Version,
 		},
 		Time:              time.Now().UTC(),
 		KubernetesVersion: serverVersion.String(),
 	}
 
-	knodes := &corev1.NodeList{}
-	if err := a.List(ctx, knodes); err != nil {
-		return err
-	}
-
-	for _, knode := range knodes.Items {
-		node, err := nodeFromKubeNode(knode)
-		if err != nil {
-			return err
+	newNodeList := func() *corev1.NodeList { return &corev1.NodeList{} }
+	if err := agent.ListPages(ctx, a.Client, newNodeList, agent.DefaultPageSize, func(knodes *corev1.NodeList) error {
+		for _, knode := range knodes.Items {
+			node, err := nodeFromKubeNode(a.anonymizer, knode)
+			if err != nil {
+				return err
+			}
+			record.Nodes = append(record.Nodes, node)
 		}
-		record.Nodes = append(record.Nodes, node)
+		return nil
+	}); err != nil {
+		return err
 	}
 
 	a.log.Infow("Collected nodes", "nodes", len(record.Nodes))
@@ -94,14 +97,9 @@ func (a kubernetesAgent) Collect(ctx context.Context) error {
 	return a.dataStore.Store(ctx, data)
 }
 
-func nodeFromKubeNode(kn corev1.Node) (v2types.Node, error) {
-	id, err := getID(kn)
-	if err != nil {
-		return v2types.Node{}, err
-	}
-
+func nodeFromKubeNode(anonymizer agent.Anonymizer, kn corev1.Node) (v2types.Node, error) {
 	n := v2types.Node{
-		ID:                      id,
+		ID:                      getID(anonymizer, kn),
 		OperatingSystem:         agent.StrPtr(kn.Status.NodeInfo.OperatingSystem),
 		OSImage:                 agent.StrPtr(kn.Status.NodeInfo.OSImage),
 		KernelVersion:           agent.StrPtr(kn.Status.NodeInfo.KernelVersion),
@@ -131,12 +129,12 @@ func nodeFromKubeNode(kn corev1.Node) (v2types.Node, error) {
 	return n, nil
 }
 
-func getID(kn corev1.Node) (string, error) {
+func getID(anonymizer agent.Anonymizer, kn corev1.Node) string {
 	// We don't want to report the node's Name - that is Personally Identifiable Information.
 	// The MachineID is apparently not always populated and SystemUUID is ill-defined. Let's
 	// just hash them all together. It should be stable, and this reduces risk
 	// of PII leakage.
-	return agent.HashOf(kn.Name + kn.Status.NodeInfo.MachineID + kn.Status.NodeInfo.SystemUUID)
+	return anonymizer.ID(kn.Name + kn.Status.NodeInfo.MachineID + kn.Status.NodeInfo.SystemUUID)
 }
 
 func getNodeExternalIP(node corev1.Node) string {
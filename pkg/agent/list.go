@@ -0,0 +1,55 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package agent
+
+import (
+	"context"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DefaultPageSize is the number of objects requested per List call.
+const DefaultPageSize = 500
+
+// ListPages lists objects in chunks of pageSize and calls fn for every page,
+// so that large lists are never held in memory at once. newList must return
+// an empty list for every page, as decoding into a used list can leave
+// fields of previous items behind.
+func ListPages[T client.ObjectList](ctx context.Context, reader client.Reader, newList func() T, pageSize int64, fn func(T) error, opts ...client.ListOption) error {
+	if pageSize <= 0 {
+		pageSize = DefaultPageSize
+	}
+
+	continueToken := ""
+	for {
+		list := newList()
+
+		pageOpts := append([]client.ListOption{client.Limit(pageSize), client.Continue(continueToken)}, opts...)
+		if err := reader.List(ctx, list, pageOpts...); err != nil {
+			return err
+		}
+
+		if err := fn(list); err != nil {
+			return err
+		}
+
+		continueToken = list.GetContinue()
+		if continueToken == "" {
+			return nil
+		}
+	}
+}
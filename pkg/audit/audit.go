@@ -0,0 +1,248 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"bufio"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	"github.com/kubermatic/telemetry-client/pkg/report"
+)
+
+const (
+	// DefaultMaxSize is the size in bytes after which the log is rotated.
+	DefaultMaxSize = 10 * 1024 * 1024
+	// DefaultMaxBackups is the number of rotated logs that are kept.
+	DefaultMaxBackups = 5
+
+	OutcomeSuccess = "success"
+	OutcomeFailure = "failure"
+)
+
+// Entry describes a single transmission.
+type Entry struct {
+	Time time.Time `json:"time"`
+	// Destination is where the payload was sent to.
+	Destination string `json:"destination"`
+	// SHA256 is the hex encoded hash of the payload as it was handed to
+	// the destination, before any compression.
+	SHA256 string `json:"sha256"`
+	// Size is the size of the payload in bytes.
+	Size int `json:"size"`
+	// Kinds lists the kind and version of every record in the payload.
+	Kinds []string `json:"kinds,omitempty"`
+	// Encrypted is true if the kinds of the payload or some of its records
+	// are unknown because they were encrypted before reaching the reporter.
+	Encrypted bool   `json:"encrypted,omitempty"`
+	Outcome   string `json:"outcome"`
+	// StatusCode is the HTTP status code of the response, if any.
+	StatusCode int    `json:"status_code,omitempty"`
+	Error      string `json:"error,omitempty"`
+}
+
+// NewEntry describes the payload, the outcome still has to be set.
+func NewEntry(destination string, payload []byte) Entry {
+	return NewEntryWithPlaintext(destination, payload, payload)
+}
+
+// NewEntryWithPlaintext is like NewEntry for a payload that was encrypted
+// before it was handed to the destination. The payload is hashed, while the
+// record kinds are taken from the plaintext.
+func NewEntryWithPlaintext(destination string, payload, plaintext []byte) Entry {
+	sum := sha256.Sum256(payload)
+	entry := Entry{
+		Time:        time.Now().UTC(),
+		Destination: destination,
+		SHA256:      hex.EncodeToString(sum[:]),
+		Size:        len(payload),
+	}
+
+	if report.IsSealed(plaintext) {
+		entry.Encrypted = true
+		return entry
+	}
+
+	var r struct {
+		Records []json.RawMessage `json:"records"`
+	}
+	if err := json.Unmarshal(plaintext, &r); err != nil {
+		return entry
+	}
+
+	for _, record := range r.Records {
+		var kv agent.KindVersion
+		if err := json.Unmarshal(record, &kv); err != nil || kv.Kind == "" {
+			entry.Encrypted = entry.Encrypted || report.IsSealed(record)
+			continue
+		}
+		entry.Kinds = append(entry.Kinds, kv.Kind+"/"+kv.Version)
+	}
+
+	return entry
+}
+
+// Log is an append-only JSONL file that is rotated when it grows too large.
+type Log struct {
+	path string
+	// maxSize is the size in bytes after which the file is rotated.
+	maxSize int64
+	// maxBackups is the number of rotated files kept as path.1 to path.N.
+	maxBackups int
+
+	lock sync.Mutex
+}
+
+func NewLog(path string, maxSize int64, maxBackups int) *Log {
+	if maxSize <= 0 {
+		maxSize = DefaultMaxSize
+	}
+	if maxBackups < 0 {
+		maxBackups = 0
+	}
+
+	return &Log{path: path, maxSize: maxSize, maxBackups: maxBackups}
+}
+
+// Append writes the entry to the log.
+func (l *Log) Append(entry Entry) error {
+	line, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	line = append(line, '\n')
+
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	if info, err := os.Stat(l.path); err == nil && info.Size()+int64(len(line)) > l.maxSize {
+		if err := l.rotate(); err != nil {
+			return fmt.Errorf("failed to rotate audit log: %w", err)
+		}
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	if _, err := f.Write(line); err != nil {
+		f.Close()
+		return err
+	}
+
+	return f.Close()
+}
+
+// Check returns an error if the log cannot be written.
+func (l *Log) Check() error {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(l.path), 0o700); err != nil {
+		return fmt.Errorf("failed to create audit log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(l.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o600)
+	if err != nil {
+		return err
+	}
+
+	return f.Close()
+}
+
+// rotate moves path.N-1 to path.N and so on, dropping the oldest file.
+func (l *Log) rotate() error {
+	if l.maxBackups == 0 {
+		return os.Remove(l.path)
+	}
+
+	for i := l.maxBackups - 1; i >= 1; i-- {
+		if err := os.Rename(l.backup(i), l.backup(i+1)); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+	}
+
+	return os.Rename(l.path, l.backup(1))
+}
+
+func (l *Log) backup(i int) string {
+	return fmt.Sprintf("%s.%d", l.path, i)
+}
+
+// Entries returns all entries of the log including the rotated files,
+// oldest first.
+func (l *Log) Entries() ([]Entry, error) {
+	l.lock.Lock()
+	defer l.lock.Unlock()
+
+	files := []string{}
+	for i := l.maxBackups; i >= 1; i-- {
+		files = append(files, l.backup(i))
+	}
+	files = append(files, l.path)
+
+	entries := []Entry{}
+	for _, file := range files {
+		fileEntries, err := readEntries(file)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, fileEntries...)
+	}
+
+	return entries, nil
+}
+
+func readEntries(filename string) ([]Entry, error) {
+	f, err := os.Open(filename)
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	entries := []Entry{}
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if len(scanner.Bytes()) == 0 {
+			continue
+		}
+
+		entry := Entry{}
+		if err := json.Unmarshal(scanner.Bytes(), &entry); err != nil {
+			return nil, fmt.Errorf("%s:%d: invalid entry: %w", filename, line, err)
+		}
+		entries = append(entries, entry)
+	}
+
+	return entries, scanner.Err()
+}
@@ -19,9 +19,14 @@ package kubermaticagent
 import (
 	"context"
 	"fmt"
+	"strings"
+	"time"
 
+	telemetryagent "github.com/kubermatic/telemetry-client/pkg/agent"
 	k8cv2 "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2"
+	"github.com/kubermatic/telemetry-client/pkg/consent"
 	"github.com/kubermatic/telemetry-client/pkg/datastore"
+	"github.com/kubermatic/telemetry-client/pkg/privacy"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -46,6 +51,28 @@ func init() {
 type flags struct {
 	// recordDir is the directory to save all records files from agents.
 	recordDir string
+	// encryptionKeyFile is the collector public key to encrypt records with.
+	encryptionKeyFile string
+	// encryptionKeyID identifies the collector public key.
+	encryptionKeyID string
+	// compress makes the agent write gzip compressed records.
+	compress bool
+	// legacyAnonymization selects the unsalted anonymization of earlier releases.
+	legacyAnonymization bool
+	// privacyPreset is the built-in privacy policy to apply to records.
+	privacyPreset string
+	// privacyPolicyFile is a privacy policy to apply on top of the preset.
+	privacyPolicyFile string
+	// seedWorkers is the number of seeds collected concurrently.
+	seedWorkers int
+	// seedTimeout is the time budget for listing the clusters of a single seed.
+	seedTimeout time.Duration
+	// userClusterWorkers is the number of user clusters of a seed visited concurrently.
+	userClusterWorkers int
+	// userClusterTimeout is the time budget for visiting the user clusters of a single seed.
+	userClusterTimeout time.Duration
+	// pageSize is the number of objects requested per List call.
+	pageSize int64
 }
 
 func NewKubermaticAgentCommand(log *zap.SugaredLogger) *cobra.Command {
@@ -61,6 +88,17 @@ func NewKubermaticAgentCommand(log *zap.SugaredLogger) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&flags.recordDir, "record-dir", "/records/", "the directory to save all records files from agents")
+	cmd.Flags().StringVar(&flags.encryptionKeyFile, "encryption-key-file", "", "the PEM encoded X25519 public key of the collector to encrypt records with (disabled if empty)")
+	cmd.Flags().StringVar(&flags.encryptionKeyID, "encryption-key-id", "", "the ID of the collector public key, stored along with encrypted records")
+	cmd.Flags().BoolVar(&flags.compress, "compress", false, "write gzip compressed records, encrypted records are compressed before they are sealed")
+	cmd.Flags().BoolVar(&flags.legacyAnonymization, "legacy-anonymization", false, fmt.Sprintf("anonymize names with the unsalted MD5 hashes of earlier releases instead of requiring a salt in %s, the identifiers of guessable names can be reversed", telemetryagent.AnonymizationSaltEnv))
+	cmd.Flags().StringVar(&flags.privacyPreset, "privacy-preset", privacy.PresetFull, fmt.Sprintf("the built-in privacy policy to apply to records, one of %s", strings.Join(privacy.Presets(), ", ")))
+	cmd.Flags().StringVar(&flags.privacyPolicyFile, "privacy-policy-file", "", "a YAML privacy policy, e.g. mounted from a ConfigMap, whose rules are applied on top of the preset")
+	cmd.Flags().IntVar(&flags.seedWorkers, "seed-workers", k8cv2.DefaultSeedWorkers, "the number of seeds to collect concurrently")
+	cmd.Flags().DurationVar(&flags.seedTimeout, "seed-timeout", k8cv2.DefaultSeedTimeout, "the time budget for describing a single seed and listing its clusters, seeds that take longer are reported as failed")
+	cmd.Flags().IntVar(&flags.userClusterWorkers, "user-cluster-workers", k8cv2.DefaultUserClusterWorkers, "the number of user clusters of a seed to visit concurrently")
+	cmd.Flags().DurationVar(&flags.userClusterTimeout, "user-cluster-timeout", k8cv2.DefaultUserClusterTimeout, "the time budget for visiting the user clusters of a single seed after its clusters were listed, clusters that are not visited in time are reported without their details")
+	cmd.Flags().Int64Var(&flags.pageSize, "page-size", telemetryagent.DefaultPageSize, "the number of objects to request per List call, lower values reduce the memory usage on large installations")
 	return cmd
 }
 
@@ -93,8 +131,57 @@ func runE(ctx context.Context, log *zap.SugaredLogger, flags *flags) error {
 		return fmt.Errorf("failed to create discovery client: %w", err)
 	}
 
-	dataStore := datastore.NewFileStore(flags.recordDir, log)
-	agent := k8cv2.NewAgent(c, discoveryClient, dataStore, log)
+	userConsent, err := consent.Get(ctx, c, consent.Namespace())
+	if err != nil {
+		return fmt.Errorf("failed to check consent: %w", err)
+	}
+	if userConsent.OptOut {
+		log.Infow("Not collecting data", "reason", userConsent.String())
+		return nil
+	}
+
+	// the consent also limits a preset set in the policy file
+	policy, err := privacy.Load(flags.privacyPreset, flags.privacyPolicyFile, userConsent.Level)
+	if err != nil {
+		return err
+	}
+	if userConsent.Level != "" {
+		log.Infow("Limiting privacy preset", "preset", policy.Preset, "reason", userConsent.String())
+	}
+
+	anonymizer, err := telemetryagent.NewAnonymizerFromEnv(flags.legacyAnonymization, log)
+	if err != nil {
+		return err
+	}
+
+	// ciphertext does not compress, so encrypted records are compressed
+	// before they are sealed
+	dataStore := datastore.NewFileStore(flags.recordDir, flags.compress && flags.encryptionKeyFile == "", log)
+	if flags.encryptionKeyFile != "" {
+		compression := datastore.CompressionNone
+		if flags.compress {
+			compression = datastore.CompressionGzip
+		}
+
+		dataStore, err = datastore.NewEncryptingStoreFromFile(dataStore, flags.encryptionKeyFile, datastore.EncryptionOptions{
+			KeyID:       flags.encryptionKeyID,
+			Compression: compression,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// filter before anything is encrypted or written
+	dataStore = datastore.NewPrivacyFilter(dataStore, policy, anonymizer)
+
+	agent := k8cv2.NewAgent(c, discoveryClient, dataStore, anonymizer, k8cv2.Options{
+		SeedWorkers:        flags.seedWorkers,
+		SeedTimeout:        flags.seedTimeout,
+		UserClusterWorkers: flags.userClusterWorkers,
+		UserClusterTimeout: flags.userClusterTimeout,
+		PageSize:           flags.pageSize,
+	}, log)
 
 	log.Info("Collecting data…")
 
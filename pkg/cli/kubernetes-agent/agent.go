@@ -19,9 +19,13 @@ package agent
 import (
 	"context"
 	"fmt"
+	"strings"
 
+	telemetryagent "github.com/kubermatic/telemetry-client/pkg/agent"
 	k8sagentv2 "github.com/kubermatic/telemetry-client/pkg/agent/kubernetes/v2"
+	"github.com/kubermatic/telemetry-client/pkg/consent"
 	"github.com/kubermatic/telemetry-client/pkg/datastore"
+	"github.com/kubermatic/telemetry-client/pkg/privacy"
 
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
@@ -44,6 +48,18 @@ func init() {
 type flags struct {
 	// recordDir is the directory to save all records files from agents.
 	recordDir string
+	// encryptionKeyFile is the collector public key to encrypt records with.
+	encryptionKeyFile string
+	// encryptionKeyID identifies the collector public key.
+	encryptionKeyID string
+	// compress makes the agent write gzip compressed records.
+	compress bool
+	// legacyAnonymization selects the unsalted anonymization of earlier releases.
+	legacyAnonymization bool
+	// privacyPreset is the built-in privacy policy to apply to records.
+	privacyPreset string
+	// privacyPolicyFile is a privacy policy to apply on top of the preset.
+	privacyPolicyFile string
 }
 
 func NewKubernetesAgentCommand(log *zap.SugaredLogger) *cobra.Command {
@@ -59,6 +75,12 @@ func NewKubernetesAgentCommand(log *zap.SugaredLogger) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&flags.recordDir, "record-dir", "/records/", "the directory to save all records files from agents")
+	cmd.Flags().StringVar(&flags.encryptionKeyFile, "encryption-key-file", "", "the PEM encoded X25519 public key of the collector to encrypt records with (disabled if empty)")
+	cmd.Flags().StringVar(&flags.encryptionKeyID, "encryption-key-id", "", "the ID of the collector public key, stored along with encrypted records")
+	cmd.Flags().BoolVar(&flags.compress, "compress", false, "write gzip compressed records, encrypted records are compressed before they are sealed")
+	cmd.Flags().BoolVar(&flags.legacyAnonymization, "legacy-anonymization", false, fmt.Sprintf("anonymize names with the unsalted MD5 hashes of earlier releases instead of requiring a salt in %s, the identifiers of guessable names can be reversed", telemetryagent.AnonymizationSaltEnv))
+	cmd.Flags().StringVar(&flags.privacyPreset, "privacy-preset", privacy.PresetFull, fmt.Sprintf("the built-in privacy policy to apply to records, one of %s", strings.Join(privacy.Presets(), ", ")))
+	cmd.Flags().StringVar(&flags.privacyPolicyFile, "privacy-policy-file", "", "a YAML privacy policy, e.g. mounted from a ConfigMap, whose rules are applied on top of the preset")
 	return cmd
 }
 
@@ -91,8 +113,51 @@ func runE(ctx context.Context, log *zap.SugaredLogger, flags *flags) error {
 		return fmt.Errorf("failed to create discovery client: %w", err)
 	}
 
-	dataStore := datastore.NewFileStore(flags.recordDir, log)
-	agent := k8sagentv2.NewAgent(c, discoveryClient, dataStore, log)
+	userConsent, err := consent.Get(ctx, c, consent.Namespace())
+	if err != nil {
+		return fmt.Errorf("failed to check consent: %w", err)
+	}
+	if userConsent.OptOut {
+		log.Infow("Not collecting data", "reason", userConsent.String())
+		return nil
+	}
+
+	// the consent also limits a preset set in the policy file
+	policy, err := privacy.Load(flags.privacyPreset, flags.privacyPolicyFile, userConsent.Level)
+	if err != nil {
+		return err
+	}
+	if userConsent.Level != "" {
+		log.Infow("Limiting privacy preset", "preset", policy.Preset, "reason", userConsent.String())
+	}
+
+	anonymizer, err := telemetryagent.NewAnonymizerFromEnv(flags.legacyAnonymization, log)
+	if err != nil {
+		return err
+	}
+
+	// ciphertext does not compress, so encrypted records are compressed
+	// before they are sealed
+	dataStore := datastore.NewFileStore(flags.recordDir, flags.compress && flags.encryptionKeyFile == "", log)
+	if flags.encryptionKeyFile != "" {
+		compression := datastore.CompressionNone
+		if flags.compress {
+			compression = datastore.CompressionGzip
+		}
+
+		dataStore, err = datastore.NewEncryptingStoreFromFile(dataStore, flags.encryptionKeyFile, datastore.EncryptionOptions{
+			KeyID:       flags.encryptionKeyID,
+			Compression: compression,
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	// filter before anything is encrypted or written
+	dataStore = datastore.NewPrivacyFilter(dataStore, policy, anonymizer)
+
+	agent := k8sagentv2.NewAgent(c, discoveryClient, dataStore, anonymizer, log)
 
 	log.Info("Collecting data…")
 
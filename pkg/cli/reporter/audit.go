@@ -0,0 +1,180 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/kubermatic/telemetry-client/pkg/audit"
+	"github.com/kubermatic/telemetry-client/pkg/datastore"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/resource"
+)
+
+const (
+	outputTable = "table"
+	outputJSON  = "json"
+)
+
+type auditFlags struct {
+	// path is the audit log file, auditing is disabled if empty.
+	path string
+	// maxSize is the size after which the audit log is rotated.
+	maxSize string
+	// maxBackups is the number of rotated audit logs that are kept.
+	maxBackups int
+}
+
+func (f *auditFlags) addFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.path, "audit-log", "", "the JSONL file to record every transmission in, should be on a persistent volume (disabled if empty)")
+	fs.StringVar(&f.maxSize, "audit-log-max-size", "10Mi", "the size after which the audit log is rotated")
+	fs.IntVar(&f.maxBackups, "audit-log-max-backups", audit.DefaultMaxBackups, "the number of rotated audit logs to keep")
+}
+
+func (f *auditFlags) newLog() (*audit.Log, error) {
+	maxSize, err := resource.ParseQuantity(f.maxSize)
+	if err != nil {
+		return nil, fmt.Errorf("invalid audit log size: %w", err)
+	}
+
+	return audit.NewLog(f.path, maxSize.Value(), f.maxBackups), nil
+}
+
+// wrap records all transmissions to the destination in the audit log, if enabled.
+func (f *auditFlags) wrap(dataStore datastore.DataStore, destination string, log *zap.SugaredLogger) (datastore.DataStore, error) {
+	if f.path == "" {
+		return dataStore, nil
+	}
+
+	auditLog, err := f.newLog()
+	if err != nil {
+		return nil, err
+	}
+
+	return datastore.NewAuditingStore(dataStore, auditLog, destination, log), nil
+}
+
+type auditListFlags struct {
+	// output is the output format, table or json.
+	output string
+	// limit is the number of most recent entries to print, 0 prints all.
+	limit int
+	// failed only prints failed transmissions.
+	failed bool
+	// destination only prints transmissions to destinations containing it.
+	destination string
+}
+
+func newAuditCommand(auditing *auditFlags) *cobra.Command {
+	flags := &auditListFlags{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "audit",
+		Short: "List past transmissions recorded in the audit log",
+		Long: `Prints the transmissions recorded in the audit log given with --audit-log,
+oldest first. Every entry contains the SHA-256 hash of the payload, so that it
+can be matched against the reports that were kept locally or by the receiver.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if auditing.path == "" {
+				return errors.New("no audit log given, use --audit-log")
+			}
+
+			auditLog, err := auditing.newLog()
+			if err != nil {
+				return err
+			}
+
+			entries, err := auditLog.Entries()
+			if err != nil {
+				return fmt.Errorf("failed to read audit log: %w", err)
+			}
+
+			filtered := []audit.Entry{}
+			for _, entry := range entries {
+				if flags.failed && entry.Outcome != audit.OutcomeFailure {
+					continue
+				}
+				if !strings.Contains(entry.Destination, flags.destination) {
+					continue
+				}
+				filtered = append(filtered, entry)
+			}
+			if flags.limit > 0 && len(filtered) > flags.limit {
+				filtered = filtered[len(filtered)-flags.limit:]
+			}
+
+			switch flags.output {
+			case outputJSON:
+				encoder := json.NewEncoder(os.Stdout)
+				encoder.SetIndent("", "  ")
+				return encoder.Encode(filtered)
+			case outputTable:
+				return printAuditTable(filtered)
+			default:
+				return fmt.Errorf("unsupported output %q, must be %s or %s", flags.output, outputTable, outputJSON)
+			}
+		},
+	}
+	cmd.Flags().StringVarP(&flags.output, "output", "o", outputTable, fmt.Sprintf("the output format, %s or %s", outputTable, outputJSON))
+	cmd.Flags().IntVar(&flags.limit, "limit", 0, "only print the most recent entries (all if 0)")
+	cmd.Flags().BoolVar(&flags.failed, "failed", false, "only print failed transmissions")
+	cmd.Flags().StringVar(&flags.destination, "destination", "", "only print transmissions to destinations containing this string")
+	return cmd
+}
+
+func printAuditTable(entries []audit.Entry) error {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIME\tDESTINATION\tOUTCOME\tSTATUS\tSIZE\tRECORDS\tSHA256\tERROR")
+
+	for _, entry := range entries {
+		status := "-"
+		if entry.StatusCode != 0 {
+			status = fmt.Sprint(entry.StatusCode)
+		}
+
+		hash := entry.SHA256
+		if len(hash) > 12 {
+			hash = hash[:12]
+		}
+
+		records := fmt.Sprint(len(entry.Kinds))
+		if entry.Encrypted {
+			records = "encrypted"
+		}
+
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%d\t%s\t%s\t%s\n",
+			entry.Time.Local().Format("2006-01-02 15:04:05"),
+			entry.Destination,
+			entry.Outcome,
+			status,
+			entry.Size,
+			records,
+			hash,
+			entry.Error,
+		)
+	}
+
+	return w.Flush()
+}
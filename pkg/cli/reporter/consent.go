@@ -0,0 +1,70 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/kubermatic/telemetry-client/pkg/consent"
+
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+type consentFlags struct {
+	// ignore skips the consent check, e.g. when running outside of a cluster.
+	ignore bool
+	// namespace is the namespace of the consent ConfigMap.
+	namespace string
+}
+
+func (f *consentFlags) addFlags(fs *pflag.FlagSet) {
+	fs.BoolVar(&f.ignore, "ignore-consent", false, "do not check the cluster for an opt-out before sending reports, e.g. when running outside of a cluster")
+	fs.StringVar(&f.namespace, "consent-namespace", consent.Namespace(), fmt.Sprintf("the namespace of the %s ConfigMap", consent.ConfigMapName))
+}
+
+// allowed returns false if the cluster opted out of telemetry.
+func (f *consentFlags) allowed(ctx context.Context, log *zap.SugaredLogger) (bool, error) {
+	if f.ignore {
+		return true, nil
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return false, fmt.Errorf("failed to get kubernetes configuration to check consent (use --ignore-consent outside of a cluster): %w", err)
+	}
+
+	c, err := client.New(cfg, client.Options{Scheme: scheme})
+	if err != nil {
+		return false, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	userConsent, err := consent.Get(ctx, c, f.namespace)
+	if err != nil {
+		return false, fmt.Errorf("failed to check consent: %w", err)
+	}
+
+	if userConsent.OptOut {
+		log.Infow("Not sending any reports", "reason", userConsent.String())
+		return false, nil
+	}
+
+	return true, nil
+}
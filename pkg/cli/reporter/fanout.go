@@ -0,0 +1,138 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/kubermatic/telemetry-client/pkg/datastore"
+	reporterv2 "github.com/kubermatic/telemetry-client/pkg/reporter/v2"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+const (
+	sinkHTTP   = "http"
+	sinkS3     = "s3"
+	sinkStdout = "stdout"
+	sinkFile   = "file"
+
+	sinkRequired   = "required"
+	sinkBestEffort = "best-effort"
+)
+
+type fanOutFlags struct {
+	// recordDir is the directory for reporter to read reports.
+	recordDir string
+	// clientUUID is the clientUUID of this reporter.
+	clientUUID string
+	// sinks are the sinks to deliver reports to, as <type>[=<arg>][,required|,best-effort].
+	sinks []string
+	// parallel delivers reports to all sinks at once.
+	parallel bool
+
+	http httpFlags
+	s3   s3Flags
+}
+
+func newFanOutReporterCommand(consent *consentFlags, auditing *auditFlags, log *zap.SugaredLogger) *cobra.Command {
+	flags := &fanOutFlags{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "fanout",
+		Short: "Telemetry reporter delivering reports to multiple sinks",
+		Long: `Delivers every report to all sinks given with --sink, in the form <type>[=<arg>][,required|,best-effort].
+
+Supported sink types are http and s3, configured by the flags of the respective
+reporter, stdout, and file=<directory> to archive reports on disk. Sinks are
+required by default, the report is only considered delivered if all required
+sinks accepted it. Failures of best-effort sinks are only logged.`,
+		Example: "  reporter fanout --sink http --url https://telemetry.example.com --sink file=/archive,best-effort",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			allowed, err := consent.allowed(cmd.Context(), log)
+			if err != nil || !allowed {
+				return err
+			}
+			sinks := make([]datastore.Sink, 0, len(flags.sinks))
+			for _, spec := range flags.sinks {
+				sink, err := flags.newSink(spec, auditing, log)
+				if err != nil {
+					return err
+				}
+				sinks = append(sinks, sink)
+			}
+			if len(sinks) == 0 {
+				return errors.New("no sinks given, use --sink")
+			}
+
+			fanOut := datastore.NewFanOut(sinks, flags.parallel, log)
+			reporter, err := reporterv2.NewFileReporter(fanOut, flags.recordDir, flags.clientUUID)
+			if err != nil {
+				return err
+			}
+			return reporter.Report(cmd.Context())
+		},
+	}
+	cmd.Flags().StringVar(&flags.recordDir, "record-dir", "/records/", "the directory for reporter to read reports")
+	cmd.Flags().StringVar(&flags.clientUUID, "client-uuid", os.Getenv("CLIENT_UUID"), "the client UUID of this reporter")
+	cmd.Flags().StringArrayVar(&flags.sinks, "sink", nil, "a sink to deliver reports to, as <type>[=<arg>][,required|,best-effort] (can be given multiple times)")
+	cmd.Flags().BoolVar(&flags.parallel, "parallel", false, "deliver reports to all sinks in parallel instead of one after another")
+	flags.http.addDataStoreFlags(cmd.Flags())
+	flags.s3.addDataStoreFlags(cmd.Flags())
+	return cmd
+}
+
+// newSink creates the sink for a single --sink value.
+func (f *fanOutFlags) newSink(spec string, auditing *auditFlags, log *zap.SugaredLogger) (datastore.Sink, error) {
+	target, policy, _ := strings.Cut(spec, ",")
+	kind, arg, _ := strings.Cut(target, "=")
+
+	sink := datastore.Sink{Name: target, Required: true}
+	switch policy {
+	case "", sinkRequired:
+	case sinkBestEffort:
+		sink.Required = false
+	default:
+		return sink, fmt.Errorf("invalid sink %q: unknown policy %q, must be %s or %s", spec, policy, sinkRequired, sinkBestEffort)
+	}
+
+	var err error
+	switch kind {
+	case sinkHTTP:
+		sink.DataStore, err = f.http.newDataStore(auditing, log)
+	case sinkS3:
+		sink.DataStore, err = f.s3.newDataStore(f.clientUUID, auditing, log)
+	case sinkStdout:
+		sink.DataStore = datastore.NewStdout()
+	case sinkFile:
+		if arg == "" {
+			return sink, fmt.Errorf("invalid sink %q: no directory given, use file=<directory>", spec)
+		}
+		sink.DataStore = datastore.NewFileStore(arg, false, log)
+	default:
+		return sink, fmt.Errorf("invalid sink %q: unknown type %q", spec, kind)
+	}
+	if err != nil {
+		return sink, fmt.Errorf("invalid sink %q: %w", spec, err)
+	}
+
+	return sink, nil
+}
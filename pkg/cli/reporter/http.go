@@ -17,13 +17,18 @@ limitations under the License.
 package reporter
 
 import (
+	"fmt"
 	"os"
+	"time"
 
 	"github.com/kubermatic/telemetry-client/pkg/datastore"
+	"github.com/kubermatic/telemetry-client/pkg/report"
 	reporterv2 "github.com/kubermatic/telemetry-client/pkg/reporter/v2"
 
 	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
 	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/api/resource"
 	"k8s.io/apimachinery/pkg/runtime"
 	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
 	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
@@ -41,16 +46,61 @@ type httpFlags struct {
 	recordDir string
 	// clientUUID is the clientUUID of this reporter.
 	clientUUID string
+	// maxAttempts is the maximum number of requests made for a single report.
+	maxAttempts int
+	// attemptTimeout is the timeout for a single request.
+	attemptTimeout time.Duration
+	// deadline is the time budget for all requests of a single report.
+	deadline time.Duration
+	// outboxDir is the directory to keep reports that could not be delivered.
+	outboxDir string
+	// outboxMaxSize is the maximum size of all reports kept in the outbox.
+	outboxMaxSize string
+	// outboxMaxAge is the maximum age of reports kept in the outbox.
+	outboxMaxAge time.Duration
+	// bearerTokenFile is the file containing the token to authenticate with.
+	bearerTokenFile string
+	// caFile is the CA bundle to verify the collector with.
+	caFile string
+	// clientCertFile is the client certificate to authenticate with.
+	clientCertFile string
+	// clientKeyFile is the key of the client certificate.
+	clientKeyFile string
+	// proxyURL is the proxy to use instead of the one configured in the environment.
+	proxyURL string
+	// headers are additional headers sent with every request.
+	headers map[string]string
+	// signingAlgorithm is the algorithm used to sign reports.
+	signingAlgorithm string
+	// signingKeyID identifies the signing key towards the collector.
+	signingKeyID string
+	// signingKeyFile is the file containing the signing key, signing is disabled if empty.
+	signingKeyFile string
+	// encryptionKeyFile is the collector public key to encrypt reports with.
+	encryptionKeyFile string
+	// encryptionKeyID identifies the collector public key.
+	encryptionKeyID string
+	// compression is the content coding used for reports.
+	compression string
+	// compressionThreshold is the minimum size of a report to be compressed.
+	compressionThreshold int
 }
 
-func newHTTPReporterCommand(log *zap.SugaredLogger) *cobra.Command {
+func newHTTPReporterCommand(consent *consentFlags, auditing *auditFlags, log *zap.SugaredLogger) *cobra.Command {
 	flags := &httpFlags{}
 	cmd := &cobra.Command{
 		Args:  cobra.NoArgs,
 		Use:   "http",
 		Short: "Telemetry http-reporter",
 		RunE: func(cmd *cobra.Command, args []string) error {
-			httpStore := datastore.NewHTTPStore(flags.url, log)
+			allowed, err := consent.allowed(cmd.Context(), log)
+			if err != nil || !allowed {
+				return err
+			}
+			httpStore, err := flags.newDataStore(auditing, log)
+			if err != nil {
+				return err
+			}
 			reporter, err := reporterv2.NewFileReporter(httpStore, flags.recordDir, flags.clientUUID)
 			if err != nil {
 				return err
@@ -59,7 +109,101 @@ func newHTTPReporterCommand(log *zap.SugaredLogger) *cobra.Command {
 		},
 	}
 	cmd.Flags().StringVar(&flags.recordDir, "record-dir", "/records/", "the directory for reporter to read reports")
-	cmd.Flags().StringVar(&flags.url, "url", "", "the URL to push reports to")
 	cmd.Flags().StringVar(&flags.clientUUID, "client-uuid", os.Getenv("CLIENT_UUID"), "the client UUID of this reporter")
+	flags.addDataStoreFlags(cmd.Flags())
 	return cmd
 }
+
+// addDataStoreFlags registers all flags that configure the HTTP datastore.
+func (f *httpFlags) addDataStoreFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.url, "url", "", "the URL to push reports to")
+	fs.IntVar(&f.maxAttempts, "max-attempts", datastore.DefaultMaxAttempts, "the maximum number of attempts to push a report")
+	fs.DurationVar(&f.attemptTimeout, "attempt-timeout", datastore.DefaultAttemptTimeout, "the timeout for a single push attempt")
+	fs.DurationVar(&f.deadline, "deadline", datastore.DefaultDeadline, "the overall time budget for pushing a report, including retries")
+	fs.StringVar(&f.outboxDir, "outbox-dir", "", "the directory to keep reports that could not be pushed, they are pushed again on the next run (disabled if empty)")
+	fs.StringVar(&f.outboxMaxSize, "outbox-max-size", "50Mi", "the maximum size of all reports kept in the outbox, oldest reports are evicted first")
+	fs.DurationVar(&f.outboxMaxAge, "outbox-max-age", 7*24*time.Hour, "the maximum age of reports kept in the outbox")
+	fs.StringVar(&f.bearerTokenFile, "bearer-token-file", "", "the file containing a bearer token to authenticate with, it is read before every request")
+	fs.StringVar(&f.caFile, "ca-file", "", "the PEM encoded CA bundle to verify the collector with instead of the system roots")
+	fs.StringVar(&f.clientCertFile, "client-cert-file", "", "the PEM encoded client certificate to authenticate with")
+	fs.StringVar(&f.clientKeyFile, "client-key-file", "", "the PEM encoded key of the client certificate")
+	fs.StringVar(&f.proxyURL, "proxy-url", "", "the proxy to send reports through, defaults to HTTPS_PROXY/NO_PROXY from the environment")
+	fs.StringToStringVar(&f.headers, "header", nil, "additional headers to send, as key=value (can be given multiple times)")
+	fs.StringVar(&f.signingAlgorithm, "signing-algorithm", report.AlgorithmEd25519, fmt.Sprintf("the algorithm to sign reports with, one of %s or %s", report.AlgorithmEd25519, report.AlgorithmHMACSHA256))
+	fs.StringVar(&f.signingKeyID, "signing-key-id", "", "the ID of the signing key, sent along with the signature")
+	fs.StringVar(&f.signingKeyFile, "signing-key-file", "", "the file containing the shared secret (hmac-sha256) or PEM encoded private key (ed25519) to sign reports with, signing is disabled if empty")
+	fs.StringVar(&f.encryptionKeyFile, "encryption-key-file", "", "the PEM encoded X25519 public key of the collector to encrypt reports with (disabled if empty)")
+	fs.StringVar(&f.encryptionKeyID, "encryption-key-id", "", "the ID of the collector public key, sent along with encrypted reports")
+	fs.StringVar(&f.compression, "compression", datastore.CompressionNone, fmt.Sprintf("the content coding to compress reports with, one of %s or %s (disabled if empty), encrypted reports are compressed before they are sealed", datastore.CompressionGzip, datastore.CompressionZstd))
+	fs.IntVar(&f.compressionThreshold, "compression-threshold", datastore.DefaultCompressionThreshold, "the minimum size in bytes of a report to be compressed")
+}
+
+// newDataStore creates the HTTP datastore, wrapped in the audit log, outbox
+// and encryption if configured.
+func (f *httpFlags) newDataStore(auditing *auditFlags, log *zap.SugaredLogger) (datastore.DataStore, error) {
+	var signer report.Signer
+	if f.signingKeyFile != "" {
+		var err error
+		signer, err = report.LoadSigner(f.signingAlgorithm, f.signingKeyID, f.signingKeyFile)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	// the sealed envelope does not shrink, so encrypted reports are
+	// compressed by the encrypting store instead
+	compression := f.compression
+	if f.encryptionKeyFile != "" {
+		compression = datastore.CompressionNone
+	}
+
+	httpStore, err := datastore.NewHTTPStore(f.url, datastore.HTTPOptions{
+		MaxAttempts:          f.maxAttempts,
+		AttemptTimeout:       f.attemptTimeout,
+		Deadline:             f.deadline,
+		BearerTokenFile:      f.bearerTokenFile,
+		CAFile:               f.caFile,
+		ClientCertFile:       f.clientCertFile,
+		ClientKeyFile:        f.clientKeyFile,
+		ProxyURL:             f.proxyURL,
+		Headers:              f.headers,
+		Signer:               signer,
+		Compression:          compression,
+		CompressionThreshold: f.compressionThreshold,
+	}, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// audit every attempt, including replays from the outbox
+	httpStore, err = auditing.wrap(httpStore, f.url, log)
+	if err != nil {
+		return nil, err
+	}
+
+	// the audit log describes the records of reports before they are sealed
+	observer, _ := httpStore.(datastore.SealObserver)
+
+	if f.outboxDir != "" {
+		maxSize, err := resource.ParseQuantity(f.outboxMaxSize)
+		if err != nil {
+			return nil, fmt.Errorf("invalid outbox size: %w", err)
+		}
+		httpStore = datastore.NewOutbox(httpStore, f.outboxDir, maxSize.Value(), f.outboxMaxAge, log)
+	}
+
+	// encrypt before anything is kept in the outbox
+	if f.encryptionKeyFile != "" {
+		httpStore, err = datastore.NewEncryptingStoreFromFile(httpStore, f.encryptionKeyFile, datastore.EncryptionOptions{
+			KeyID:                f.encryptionKeyID,
+			Compression:          f.compression,
+			CompressionThreshold: f.compressionThreshold,
+			Observer:             observer,
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	return httpStore, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"os"
+	"time"
+
+	"github.com/kubermatic/telemetry-client/pkg/datastore"
+	reporterv2 "github.com/kubermatic/telemetry-client/pkg/reporter/v2"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type otlpFlags struct {
+	// recordDir is the directory for reporter to read reports.
+	recordDir string
+	// clientUUID is the clientUUID of this reporter.
+	clientUUID string
+	// endpoint is the base URL of the OTLP/HTTP receiver.
+	endpoint string
+	// timeout is the timeout for a single export request.
+	timeout time.Duration
+	// headers are additional headers sent with every request.
+	headers map[string]string
+	// caFile is the CA bundle to verify the receiver with.
+	caFile string
+	// clientCertFile is the client certificate to authenticate with.
+	clientCertFile string
+	// clientKeyFile is the key of the client certificate.
+	clientKeyFile string
+}
+
+func newOTLPReporterCommand(consent *consentFlags, auditing *auditFlags, log *zap.SugaredLogger) *cobra.Command {
+	flags := &otlpFlags{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "otlp",
+		Short: "Telemetry reporter exporting metrics and logs via OTLP/HTTP",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			allowed, err := consent.allowed(cmd.Context(), log)
+			if err != nil || !allowed {
+				return err
+			}
+			otlpStore, err := datastore.NewOTLPStore(datastore.OTLPOptions{
+				Endpoint:       flags.endpoint,
+				Timeout:        flags.timeout,
+				Headers:        flags.headers,
+				CAFile:         flags.caFile,
+				ClientCertFile: flags.clientCertFile,
+				ClientKeyFile:  flags.clientKeyFile,
+			}, log)
+			if err != nil {
+				return err
+			}
+			otlpStore, err = auditing.wrap(otlpStore, flags.endpoint, log)
+			if err != nil {
+				return err
+			}
+			reporter, err := reporterv2.NewFileReporter(otlpStore, flags.recordDir, flags.clientUUID)
+			if err != nil {
+				return err
+			}
+			return reporter.Report(cmd.Context())
+		},
+	}
+	cmd.Flags().StringVar(&flags.recordDir, "record-dir", "/records/", "the directory for reporter to read reports")
+	cmd.Flags().StringVar(&flags.clientUUID, "client-uuid", os.Getenv("CLIENT_UUID"), "the client UUID of this reporter")
+	cmd.Flags().StringVar(&flags.endpoint, "endpoint", datastore.DefaultOTLPEndpoint, "the base URL of the OTLP/HTTP receiver, metrics and logs are sent to /v1/metrics and /v1/logs")
+	cmd.Flags().DurationVar(&flags.timeout, "timeout", datastore.DefaultAttemptTimeout, "the timeout for a single export request")
+	cmd.Flags().StringToStringVar(&flags.headers, "header", nil, "additional headers to send, as key=value (can be given multiple times)")
+	cmd.Flags().StringVar(&flags.caFile, "ca-file", "", "the PEM encoded CA bundle to verify the receiver with instead of the system roots")
+	cmd.Flags().StringVar(&flags.clientCertFile, "client-cert-file", "", "the PEM encoded client certificate to authenticate with")
+	cmd.Flags().StringVar(&flags.clientKeyFile, "client-key-file", "", "the PEM encoded key of the client certificate")
+	return cmd
+}
@@ -0,0 +1,274 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	telemetryagent "github.com/kubermatic/telemetry-client/pkg/agent"
+	k8cv2 "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2"
+	k8sagentv2 "github.com/kubermatic/telemetry-client/pkg/agent/kubernetes/v2"
+	"github.com/kubermatic/telemetry-client/pkg/consent"
+	"github.com/kubermatic/telemetry-client/pkg/datastore"
+	"github.com/kubermatic/telemetry-client/pkg/privacy"
+	v2 "github.com/kubermatic/telemetry-client/pkg/report/v2"
+	telemetryversion "github.com/kubermatic/telemetry-client/pkg/version"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+	kubermaticv1 "k8c.io/kubermatic/v2/pkg/apis/kubermatic/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	utilruntime "k8s.io/apimachinery/pkg/util/runtime"
+	"k8s.io/client-go/discovery"
+	clientgoscheme "k8s.io/client-go/kubernetes/scheme"
+	"k8s.io/client-go/rest"
+	ctrl "sigs.k8s.io/controller-runtime"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/client/apiutil"
+	"sigs.k8s.io/yaml"
+)
+
+const outputYAML = "yaml"
+
+var previewScheme = runtime.NewScheme()
+
+func init() {
+	utilruntime.Must(kubermaticv1.AddToScheme(previewScheme))
+	utilruntime.Must(clientgoscheme.AddToScheme(previewScheme))
+}
+
+type previewFlags struct {
+	// clientUUID is the clientUUID of this reporter.
+	clientUUID string
+	// agents are the agents to run.
+	agents []string
+	// legacyAnonymization selects the unsalted anonymization of earlier releases.
+	legacyAnonymization bool
+	// privacyPreset is the built-in privacy policy to apply to records.
+	privacyPreset string
+	// privacyPolicyFile is a privacy policy to apply on top of the preset.
+	privacyPolicyFile string
+	// output is the output format, json, yaml or table.
+	output string
+}
+
+func newPreviewCommand(consent *consentFlags, log *zap.SugaredLogger) *cobra.Command {
+	flags := &previewFlags{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "preview",
+		Short: "Show the report that would be sent, without sending it",
+		Long: `Runs the agents in memory against the current cluster, applies anonymization
+and the privacy policy exactly like the agents do and prints the resulting
+report. Nothing is written to the record directory and nothing is sent.
+
+The agents use the ANONYMIZATION_SALT environment variable like in the
+CronJob, so set it to the same salt to see the identifiers that would be sent.
+Without a salt the preview fails, unless --legacy-anonymization is given.`,
+		Example: "  reporter preview --privacy-preset standard --output yaml",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			report, err := flags.collect(cmd.Context(), consent, log)
+			if err != nil {
+				return err
+			}
+
+			return printReport(report, flags.output)
+		},
+	}
+	cmd.Flags().StringVar(&flags.clientUUID, "client-uuid", os.Getenv("CLIENT_UUID"), "the client UUID of this reporter")
+	cmd.Flags().StringSliceVar(&flags.agents, "agent", []string{telemetryagent.KindKubernetes, telemetryagent.KindKubermatic}, fmt.Sprintf("the agents to run, %s and/or %s", telemetryagent.KindKubernetes, telemetryagent.KindKubermatic))
+	cmd.Flags().BoolVar(&flags.legacyAnonymization, "legacy-anonymization", false, fmt.Sprintf("anonymize names with the unsalted MD5 hashes of earlier releases instead of requiring a salt in %s, the identifiers of guessable names can be reversed", telemetryagent.AnonymizationSaltEnv))
+	cmd.Flags().StringVar(&flags.privacyPreset, "privacy-preset", privacy.PresetFull, fmt.Sprintf("the built-in privacy policy to apply to records, one of %s", strings.Join(privacy.Presets(), ", ")))
+	cmd.Flags().StringVar(&flags.privacyPolicyFile, "privacy-policy-file", "", "a YAML privacy policy whose rules are applied on top of the preset")
+	cmd.Flags().StringVarP(&flags.output, "output", "o", outputJSON, fmt.Sprintf("the output format, %s, %s or %s", outputJSON, outputYAML, outputTable))
+	return cmd
+}
+
+// collect runs the agents and assembles the report like the reporter would.
+func (f *previewFlags) collect(ctx context.Context, consentFlags *consentFlags, log *zap.SugaredLogger) (*v2.Report, error) {
+	switch f.output {
+	case outputJSON, outputYAML, outputTable:
+	default:
+		return nil, fmt.Errorf("unsupported output %q, must be %s, %s or %s", f.output, outputJSON, outputYAML, outputTable)
+	}
+
+	cfg, err := ctrl.GetConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get kubernetes configuration: %w", err)
+	}
+
+	httpClient, err := rest.HTTPClientFor(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP client: %w", err)
+	}
+
+	mapper, err := apiutil.NewDynamicRESTMapper(cfg, httpClient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create rest mapper: %w", err)
+	}
+
+	c, err := client.New(cfg, client.Options{
+		Scheme: previewScheme,
+		Mapper: mapper,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create client: %w", err)
+	}
+
+	discoveryClient, err := discovery.NewDiscoveryClientForConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create discovery client: %w", err)
+	}
+
+	userConsent := &consent.Consent{}
+	if !consentFlags.ignore {
+		userConsent, err = consent.Get(ctx, c, consentFlags.namespace)
+		if err != nil {
+			return nil, fmt.Errorf("failed to check consent: %w", err)
+		}
+		// still show the report, so that it can be reviewed before opting in
+		if userConsent.OptOut {
+			log.Warnw("Telemetry is disabled, this report would not be sent", "reason", userConsent.String())
+		}
+	}
+
+	// the consent also limits a preset set in the policy file
+	policy, err := privacy.Load(f.privacyPreset, f.privacyPolicyFile, userConsent.Level)
+	if err != nil {
+		return nil, err
+	}
+	if userConsent.Level != "" {
+		log.Infow("Limiting privacy preset", "preset", policy.Preset, "reason", userConsent.String())
+	}
+
+	anonymizer, err := telemetryagent.NewAnonymizerFromEnv(f.legacyAnonymization, log)
+	if err != nil {
+		return nil, err
+	}
+
+	memory := datastore.NewMemoryStore()
+	dataStore := datastore.NewPrivacyFilter(memory, policy, anonymizer)
+
+	for _, kind := range f.agents {
+		var agent telemetryagent.Agent
+		switch kind {
+		case telemetryagent.KindKubernetes:
+			agent = k8sagentv2.NewAgent(c, discoveryClient, dataStore, anonymizer, log)
+		case telemetryagent.KindKubermatic:
+			agent = k8cv2.NewAgent(c, discoveryClient, dataStore, anonymizer, k8cv2.Options{}, log)
+		default:
+			return nil, fmt.Errorf("unknown agent %q, must be %s or %s", kind, telemetryagent.KindKubernetes, telemetryagent.KindKubermatic)
+		}
+
+		log.Infow("Collecting data…", "agent", kind)
+
+		if err := agent.Collect(ctx); err != nil {
+			return nil, fmt.Errorf("%s agent failed: %w", kind, err)
+		}
+	}
+
+	return &v2.Report{
+		Version:    telemetryversion.V2Version,
+		Time:       time.Now().UTC(),
+		ClientUUID: f.clientUUID,
+		Records:    memory.Data(),
+	}, nil
+}
+
+func printReport(report *v2.Report, output string) error {
+	switch output {
+	case outputYAML:
+		data, err := json.Marshal(report)
+		if err != nil {
+			return err
+		}
+		data, err = yaml.JSONToYAML(data)
+		if err != nil {
+			return err
+		}
+		_, err = os.Stdout.Write(data)
+		return err
+
+	case outputTable:
+		return printReportTable(report)
+
+	default:
+		encoder := json.NewEncoder(os.Stdout)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(report)
+	}
+}
+
+// printReportTable prints a summary of every record.
+func printReportTable(report *v2.Report) error {
+	records, err := report.DecodeRecords()
+	if err != nil {
+		return err
+	}
+
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(w, "Client UUID:\t%s\n", report.ClientUUID)
+	fmt.Fprintf(w, "Records:\t%d\n\n", len(report.Records))
+
+	fmt.Fprintln(w, "KIND\tVERSION\tSUMMARY")
+	for _, record := range records.Kubernetes {
+		fmt.Fprintf(w, "%s\t%s\tKubernetes %s, %d nodes\n", record.Kind, record.Version, record.KubernetesVersion, len(record.Nodes))
+	}
+	for _, record := range records.Kubermatic {
+		fmt.Fprintf(w, "%s\t%s\tKKP %s %s, %d seeds, %d clusters, %d projects, %d users, %d SSH keys\n",
+			record.Kind, record.Version, record.KubermaticEdition, record.KubermaticVersion,
+			len(record.Seeds), len(record.Clusters), len(record.Projects), len(record.Users), len(record.SSHKeys))
+	}
+	if records.Skipped > 0 {
+		fmt.Fprintf(w, "-\t-\t%d records of unknown kind\n", records.Skipped)
+	}
+
+	if err := w.Flush(); err != nil {
+		return err
+	}
+
+	for _, record := range records.Kubernetes {
+		if len(record.Nodes) == 0 {
+			continue
+		}
+
+		fmt.Println()
+		w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+		fmt.Fprintln(w, "NODE\tOS IMAGE\tARCH\tKUBELET\tPROVIDER\tEXTERNAL IP")
+		for _, node := range record.Nodes {
+			fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", node.ID, deref(node.OSImage), deref(node.Architecture), deref(node.KubeletVersion), deref(node.CloudProvider), node.ExternalIP)
+		}
+		if err := w.Flush(); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return "-"
+	}
+	return *s
+}
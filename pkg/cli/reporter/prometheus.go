@@ -0,0 +1,154 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/kubermatic/telemetry-client/pkg/datastore"
+	reporterv2 "github.com/kubermatic/telemetry-client/pkg/reporter/v2"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+)
+
+type prometheusFlags struct {
+	// recordDir is the directory for reporter to read reports.
+	recordDir string
+	// clientUUID is the clientUUID of this reporter.
+	clientUUID string
+	// listenAddress is the address to serve /metrics on, metrics are not served if empty.
+	listenAddress string
+	// interval is the interval to read the records again while serving metrics.
+	interval time.Duration
+	// pushURL is the Pushgateway to push metrics to, metrics are not pushed if empty.
+	pushURL string
+	// job is the job label of pushed metrics.
+	job string
+}
+
+func newPrometheusReporterCommand(consent *consentFlags, auditing *auditFlags, log *zap.SugaredLogger) *cobra.Command {
+	flags := &prometheusFlags{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "prometheus",
+		Short: "Telemetry reporter exposing records as Prometheus metrics",
+		Long: `Translates the records into Prometheus gauges. With --listen-address the metrics
+are served on /metrics and the records are read again every --interval, with
+--push-url they are pushed to a Pushgateway after every read.`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			allowed, err := consent.allowed(cmd.Context(), log)
+			if err != nil || !allowed {
+				return err
+			}
+			if flags.listenAddress == "" && flags.pushURL == "" {
+				return errors.New("either --listen-address or --push-url is required")
+			}
+
+			registry := prometheus.NewRegistry()
+			prometheusStore, err := datastore.NewPrometheusStore(registry, flags.pushURL, flags.job, log)
+			if err != nil {
+				return err
+			}
+			// serving metrics does not transmit anything by itself
+			if flags.pushURL != "" {
+				prometheusStore, err = auditing.wrap(prometheusStore, flags.pushURL, log)
+				if err != nil {
+					return err
+				}
+			}
+			reporter, err := reporterv2.NewFileReporter(prometheusStore, flags.recordDir, flags.clientUUID)
+			if err != nil {
+				return err
+			}
+
+			if err := reporter.Report(cmd.Context()); err != nil {
+				return err
+			}
+			if flags.listenAddress == "" {
+				return nil
+			}
+
+			registry.MustRegister(
+				collectors.NewGoCollector(),
+				collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+			)
+
+			ctx, cancel := signal.NotifyContext(cmd.Context(), os.Interrupt, syscall.SIGTERM)
+			defer cancel()
+
+			return serveMetrics(ctx, flags.listenAddress, flags.interval, registry, reporter.Report, log)
+		},
+	}
+	cmd.Flags().StringVar(&flags.recordDir, "record-dir", "/records/", "the directory for reporter to read reports")
+	cmd.Flags().StringVar(&flags.clientUUID, "client-uuid", os.Getenv("CLIENT_UUID"), "the client UUID of this reporter")
+	cmd.Flags().StringVar(&flags.listenAddress, "listen-address", "", "the address to serve metrics on /metrics, e.g. :9090 (disabled if empty)")
+	cmd.Flags().DurationVar(&flags.interval, "interval", time.Minute, "the interval to read the records again while serving metrics")
+	cmd.Flags().StringVar(&flags.pushURL, "push-url", "", "the URL of a Pushgateway to push metrics to (disabled if empty)")
+	cmd.Flags().StringVar(&flags.job, "job", datastore.DefaultPrometheusJob, "the job label of pushed metrics")
+	return cmd
+}
+
+// serveMetrics serves the registry until the context is done, calling
+// refresh every interval.
+func serveMetrics(ctx context.Context, address string, interval time.Duration, registry *prometheus.Registry, refresh func(context.Context) error, log *zap.SugaredLogger) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(registry, promhttp.HandlerOpts{Registry: registry}))
+
+	server := &http.Server{
+		Addr:              address,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+
+	serverErr := make(chan error, 1)
+	go func() {
+		log.Infow("Serving metrics", "address", address)
+		serverErr <- server.ListenAndServe()
+	}()
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case err := <-serverErr:
+			return err
+
+		case <-ticker.C:
+			// keep serving the previous metrics if the records cannot be read
+			if err := refresh(ctx); err != nil {
+				log.Warnw("Failed to update metrics", "error", err)
+			}
+
+		case <-ctx.Done():
+			shutdownCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+			defer cancel()
+
+			return server.Shutdown(shutdownCtx)
+		}
+	}
+}
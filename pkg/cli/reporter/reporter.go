@@ -28,9 +28,20 @@ func NewReporterCommand(log *zap.SugaredLogger) *cobra.Command {
 		Short: "Telemetry reporter",
 	}
 
+	consent := &consentFlags{}
+	consent.addFlags(cmd.PersistentFlags())
+	auditing := &auditFlags{}
+	auditing.addFlags(cmd.PersistentFlags())
+
 	cmd.AddCommand(
 		newStdoutReporterCommand(),
-		newHTTPReporterCommand(log),
+		newHTTPReporterCommand(consent, auditing, log),
+		newS3ReporterCommand(consent, auditing, log),
+		newFanOutReporterCommand(consent, auditing, log),
+		newOTLPReporterCommand(consent, auditing, log),
+		newPrometheusReporterCommand(consent, auditing, log),
+		newAuditCommand(auditing),
+		newPreviewCommand(consent, log),
 	)
 	return cmd
 }
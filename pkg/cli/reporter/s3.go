@@ -0,0 +1,111 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package reporter
+
+import (
+	"os"
+
+	"github.com/kubermatic/telemetry-client/pkg/datastore"
+	reporterv2 "github.com/kubermatic/telemetry-client/pkg/reporter/v2"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"go.uber.org/zap"
+)
+
+type s3Flags struct {
+	// recordDir is the directory for reporter to read reports.
+	recordDir string
+	// clientUUID is the clientUUID of this reporter.
+	clientUUID string
+	endpoint   string
+	insecure   bool
+	pathStyle  bool
+	region     string
+	bucket     string
+	// keyPrefix is the template for the prefix of uploaded objects.
+	keyPrefix   string
+	accessKeyID string
+	// secretAccessKeyFile is the file containing the static secret access key.
+	secretAccessKeyFile string
+	// credentialsFile is an AWS shared credentials file.
+	credentialsFile string
+	profile         string
+}
+
+func newS3ReporterCommand(consent *consentFlags, auditing *auditFlags, log *zap.SugaredLogger) *cobra.Command {
+	flags := &s3Flags{}
+	cmd := &cobra.Command{
+		Args:  cobra.NoArgs,
+		Use:   "s3",
+		Short: "Telemetry s3-reporter",
+		RunE: func(cmd *cobra.Command, args []string) error {
+			allowed, err := consent.allowed(cmd.Context(), log)
+			if err != nil || !allowed {
+				return err
+			}
+			s3Store, err := flags.newDataStore(flags.clientUUID, auditing, log)
+			if err != nil {
+				return err
+			}
+			reporter, err := reporterv2.NewFileReporter(s3Store, flags.recordDir, flags.clientUUID)
+			if err != nil {
+				return err
+			}
+			return reporter.Report(cmd.Context())
+		},
+	}
+	cmd.Flags().StringVar(&flags.recordDir, "record-dir", "/records/", "the directory for reporter to read reports")
+	cmd.Flags().StringVar(&flags.clientUUID, "client-uuid", os.Getenv("CLIENT_UUID"), "the client UUID of this reporter")
+	flags.addDataStoreFlags(cmd.Flags())
+	return cmd
+}
+
+// addDataStoreFlags registers all flags that configure the S3 datastore.
+func (f *s3Flags) addDataStoreFlags(fs *pflag.FlagSet) {
+	fs.StringVar(&f.endpoint, "endpoint", "s3.amazonaws.com", "the host[:port] of the S3-compatible API")
+	fs.BoolVar(&f.insecure, "insecure", false, "use plain HTTP instead of HTTPS")
+	fs.BoolVar(&f.pathStyle, "path-style", false, "use path-style bucket addressing, required by most self-hosted S3 implementations")
+	fs.StringVar(&f.region, "region", "", "the region of the bucket")
+	fs.StringVar(&f.bucket, "bucket", "", "the bucket to upload reports to")
+	fs.StringVar(&f.keyPrefix, "key-prefix", datastore.DefaultS3KeyPrefix, "the object key prefix, a Go template that can use .ClientUUID, .Date, .Year, .Month and .Day")
+	fs.StringVar(&f.accessKeyID, "access-key-id", "", "the static access key ID, the credentials default to AWS_ACCESS_KEY_ID/AWS_SECRET_ACCESS_KEY or MINIO_ACCESS_KEY/MINIO_SECRET_KEY from the environment")
+	fs.StringVar(&f.secretAccessKeyFile, "secret-access-key-file", "", "the file containing the static secret access key, required with --access-key-id")
+	fs.StringVar(&f.credentialsFile, "credentials-file", "", "an AWS shared credentials file to read the credentials from")
+	fs.StringVar(&f.profile, "profile", "", "the profile to use from the credentials file")
+}
+
+func (f *s3Flags) newDataStore(clientUUID string, auditing *auditFlags, log *zap.SugaredLogger) (datastore.DataStore, error) {
+	s3Store, err := datastore.NewS3Store(datastore.S3Options{
+		Endpoint:            f.endpoint,
+		Insecure:            f.insecure,
+		PathStyle:           f.pathStyle,
+		Region:              f.region,
+		Bucket:              f.bucket,
+		KeyPrefix:           f.keyPrefix,
+		ClientUUID:          clientUUID,
+		AccessKeyID:         f.accessKeyID,
+		SecretAccessKeyFile: f.secretAccessKeyFile,
+		CredentialsFile:     f.credentialsFile,
+		Profile:             f.profile,
+	}, log)
+	if err != nil {
+		return nil, err
+	}
+
+	return auditing.wrap(s3Store, "s3://"+f.bucket, log)
+}
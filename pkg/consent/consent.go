@@ -0,0 +1,168 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package consent
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/kubermatic/telemetry-client/pkg/privacy"
+
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	// ConfigMapName is the ConfigMap in the telemetry namespace that
+	// controls telemetry.
+	ConfigMapName = "telemetry-consent"
+	// EnabledKey is the ConfigMap key to opt out of telemetry by setting it to "false".
+	EnabledKey = "enabled"
+	// LevelKey is the ConfigMap key holding the consent level, the name of
+	// the most permissive privacy preset that may be used.
+	LevelKey = "level"
+
+	// EnabledAnnotation opts out of telemetry if set to "false" on the
+	// KubermaticConfiguration.
+	EnabledAnnotation = "telemetry.k8c.io/enabled"
+	// LevelAnnotation sets the consent level on the KubermaticConfiguration.
+	LevelAnnotation = "telemetry.k8c.io/level"
+
+	// DefaultNamespace is the namespace telemetry is installed into.
+	DefaultNamespace = "telemetry-system"
+
+	namespaceFile = "/var/run/secrets/kubernetes.io/serviceaccount/namespace"
+)
+
+var kubermaticConfigurationList = schema.GroupVersionKind{
+	Group:   "kubermatic.k8c.io",
+	Version: "v1",
+	Kind:    "KubermaticConfigurationList",
+}
+
+// Consent is what the cluster administrators agreed to.
+type Consent struct {
+	// OptOut is true if no telemetry data may be collected or sent.
+	OptOut bool
+	// Level is the most permissive privacy preset that may be used, empty
+	// if there is no restriction.
+	Level string
+	// Source names where the opt-out or level was configured.
+	Source string
+}
+
+// String explains the consent for logging.
+func (c *Consent) String() string {
+	switch {
+	case c.OptOut:
+		return fmt.Sprintf("telemetry is disabled by %s", c.Source)
+	case c.Level != "":
+		return fmt.Sprintf("telemetry is limited to %q by %s", c.Level, c.Source)
+	default:
+		return "telemetry is enabled"
+	}
+}
+
+// Namespace returns the namespace the process is running in, falling back
+// to DefaultNamespace.
+func Namespace() string {
+	if namespace := os.Getenv("POD_NAMESPACE"); namespace != "" {
+		return namespace
+	}
+	if data, err := os.ReadFile(namespaceFile); err == nil {
+		if namespace := strings.TrimSpace(string(data)); namespace != "" {
+			return namespace
+		}
+	}
+
+	return DefaultNamespace
+}
+
+// +kubebuilder:rbac:groups="",resources=configmaps,verbs=get,resourceNames=telemetry-consent
+// +kubebuilder:rbac:groups="kubermatic.k8c.io",resources=kubermaticconfigurations,verbs=list
+
+// Get reads the consent from the ConfigMap in the given namespace and the
+// annotations of all KubermaticConfigurations. If they disagree, the most
+// restrictive setting wins.
+func Get(ctx context.Context, c client.Reader, namespace string) (*Consent, error) {
+	consent := &Consent{}
+
+	configMap := &corev1.ConfigMap{}
+	err := c.Get(ctx, client.ObjectKey{Namespace: namespace, Name: ConfigMapName}, configMap)
+	switch {
+	case apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to get ConfigMap %s/%s: %w", namespace, ConfigMapName, err)
+	default:
+		source := fmt.Sprintf("ConfigMap %s/%s", namespace, ConfigMapName)
+		if err := consent.merge(configMap.Data[EnabledKey], configMap.Data[LevelKey], source); err != nil {
+			return nil, err
+		}
+	}
+
+	// KubermaticConfigurations are only available on KKP master clusters
+	configs := &unstructured.UnstructuredList{}
+	configs.SetGroupVersionKind(kubermaticConfigurationList)
+	err = c.List(ctx, configs)
+	switch {
+	case meta.IsNoMatchError(err) || apierrors.IsNotFound(err):
+	case err != nil:
+		return nil, fmt.Errorf("failed to list KubermaticConfigurations: %w", err)
+	default:
+		for _, config := range configs.Items {
+			annotations := config.GetAnnotations()
+			source := fmt.Sprintf("KubermaticConfiguration %s/%s", config.GetNamespace(), config.GetName())
+			if err := consent.merge(annotations[EnabledAnnotation], annotations[LevelAnnotation], source); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	return consent, nil
+}
+
+func (c *Consent) merge(enabled, level, source string) error {
+	if enabled != "" {
+		value, err := strconv.ParseBool(enabled)
+		if err != nil {
+			return fmt.Errorf("invalid value %q for enabled in %s: %w", enabled, source, err)
+		}
+		if !value && !c.OptOut {
+			c.OptOut = true
+			c.Source = source
+		}
+	}
+
+	if level != "" && !c.OptOut {
+		if !privacy.IsPreset(level) {
+			return fmt.Errorf("invalid consent level %q in %s, must be one of %s", level, source, strings.Join(privacy.Presets(), ", "))
+		}
+		if c.Level == "" || privacy.MoreRestrictive(c.Level, level) != c.Level {
+			c.Level = level
+			c.Source = source
+		}
+	}
+
+	return nil
+}
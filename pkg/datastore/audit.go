@@ -0,0 +1,128 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kubermatic/telemetry-client/pkg/audit"
+
+	"go.uber.org/zap"
+)
+
+// statusStore is implemented by datastores that receive a response, like
+// the HTTP store, so that its status code can be audited.
+type statusStore interface {
+	storeWithStatus(ctx context.Context, data json.RawMessage) (int, error)
+}
+
+// SealObserver is told about every payload that the encrypting store seals.
+type SealObserver interface {
+	Sealed(sealed, plaintext []byte)
+}
+
+// auditingStore records every payload passed to the wrapped DataStore
+// along with the outcome in the audit log. As a SealObserver, it describes
+// the records of payloads sealed by an encrypting store in front of it.
+type auditingStore struct {
+	dataStore   DataStore
+	auditLog    *audit.Log
+	destination string
+	log         *zap.SugaredLogger
+
+	lock sync.Mutex
+	// plaintexts maps the hash of sealed payloads to their plaintext.
+	plaintexts map[[sha256.Size]byte][]byte
+}
+
+func NewAuditingStore(dataStore DataStore, auditLog *audit.Log, destination string, log *zap.SugaredLogger) DataStore {
+	return &auditingStore{
+		dataStore:   dataStore,
+		auditLog:    auditLog,
+		destination: destination,
+		log:         log,
+		plaintexts:  map[[sha256.Size]byte][]byte{},
+	}
+}
+
+func (s *auditingStore) Sealed(sealed, plaintext []byte) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.plaintexts[sha256.Sum256(sealed)] = plaintext
+}
+
+// plaintext returns the plaintext of data if it was sealed by an observed
+// encrypting store. Payloads replayed from the outbox by a later run are
+// not matched.
+func (s *auditingStore) plaintext(data []byte) ([]byte, bool) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	sum := sha256.Sum256(data)
+	plaintext, ok := s.plaintexts[sum]
+	delete(s.plaintexts, sum)
+
+	return plaintext, ok
+}
+
+func (s *auditingStore) Store(ctx context.Context, data json.RawMessage) error {
+	// nothing is sent without being audited, the outbox keeps the payload
+	// until the audit log can be written again
+	if err := s.auditLog.Check(); err != nil {
+		return fmt.Errorf("audit log is not writable: %w", err)
+	}
+
+	// hash what is sent, but describe the records before they were encrypted
+	entry := audit.NewEntry(s.destination, data)
+	if plaintext, ok := s.plaintext(data); ok {
+		entry = audit.NewEntryWithPlaintext(s.destination, data, plaintext)
+	}
+
+	var statusCode int
+	var err error
+	if store, ok := s.dataStore.(statusStore); ok {
+		statusCode, err = store.storeWithStatus(ctx, data)
+	} else {
+		err = s.dataStore.Store(ctx, data)
+	}
+
+	entry.Outcome = audit.OutcomeSuccess
+	entry.StatusCode = statusCode
+	if err != nil {
+		entry.Outcome = audit.OutcomeFailure
+		entry.Error = err.Error()
+
+		var statusErr *StatusError
+		if errors.As(err, &statusErr) {
+			entry.StatusCode = statusErr.StatusCode
+		}
+	}
+
+	// the payload was already handed over, failing now would only make the
+	// outbox send it again
+	if auditErr := s.auditLog.Append(entry); auditErr != nil {
+		s.log.Errorw("Failed to write audit log", "error", auditErr)
+	}
+
+	return err
+}
@@ -0,0 +1,36 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"github.com/kubermatic/telemetry-client/pkg/report"
+)
+
+const (
+	CompressionNone = report.EncodingNone
+	CompressionGzip = report.EncodingGzip
+	CompressionZstd = report.EncodingZstd
+
+	// DefaultCompressionThreshold is the payload size in bytes below which
+	// compression is not worth the effort.
+	DefaultCompressionThreshold = 1024
+)
+
+// compress encodes the data with the given content coding.
+func compress(data []byte, encoding string) ([]byte, error) {
+	return report.Compress(data, encoding)
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/prometheus/client_golang/prometheus"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+)
+
+// labelSeparator cannot be part of a label value reported by the agents.
+const labelSeparator = "\x00"
+
+// labelCounter counts occurrences of label value combinations.
+type labelCounter map[string]int
+
+func (c labelCounter) inc(values ...string) {
+	c[strings.Join(values, labelSeparator)]++
+}
+
+// each calls fn for all label value combinations in a stable order.
+func (c labelCounter) each(fn func(values []string, count int)) {
+	keys := make([]string, 0, len(c))
+	for key := range c {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	for _, key := range keys {
+		fn(strings.Split(key, labelSeparator), c[key])
+	}
+}
+
+func (c labelCounter) metrics(desc *prometheus.Desc) []prometheus.Metric {
+	metrics := make([]prometheus.Metric, 0, len(c))
+	c.each(func(values []string, count int) {
+		metrics = append(metrics, prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, float64(count), values...))
+	})
+
+	return metrics
+}
+
+// otlpGauge converts the counts into a gauge with one data point per
+// label value combination, named by keys.
+func (c labelCounter) otlpGauge(name, description, unit string, timestamp uint64, keys ...string) *metricspb.Metric {
+	dataPoints := make([]*metricspb.NumberDataPoint, 0, len(c))
+	c.each(func(values []string, count int) {
+		dataPoint := otlpDataPoint(timestamp, count)
+		for i, key := range keys {
+			dataPoint.Attributes = append(dataPoint.Attributes, otlpString(key, values[i]))
+		}
+		dataPoints = append(dataPoints, dataPoint)
+	})
+
+	return otlpGauge(name, description, unit, dataPoints...)
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"crypto/ecdh"
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"github.com/kubermatic/telemetry-client/pkg/report"
+)
+
+// encryptingStore seals every payload to the collector's public key before
+// passing it on, so that neither the shared record volume nor any proxy on
+// the way sees the plaintext.
+type encryptingStore struct {
+	dataStore DataStore
+	recipient *ecdh.PublicKey
+	opts      EncryptionOptions
+}
+
+// EncryptionOptions configures the encrypting store.
+type EncryptionOptions struct {
+	// KeyID names the recipient key, it is kept in the envelope.
+	KeyID string
+	// Compression is the encoding used to compress payloads before they
+	// are sealed, one of CompressionNone, CompressionGzip or
+	// CompressionZstd.
+	Compression string
+	// CompressionThreshold is the minimum payload size in bytes that
+	// is compressed.
+	CompressionThreshold int
+	// Observer is told about every sealed payload, e.g. an auditing
+	// store that describes the records before they were encrypted.
+	Observer SealObserver
+}
+
+func NewEncryptingStore(dataStore DataStore, recipient *ecdh.PublicKey, opts EncryptionOptions) (DataStore, error) {
+	switch opts.Compression {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", opts.Compression)
+	}
+
+	return encryptingStore{dataStore: dataStore, recipient: recipient, opts: opts}, nil
+}
+
+// NewEncryptingStoreFromFile is like NewEncryptingStore, but reads the
+// PEM encoded X25519 public key from a file.
+func NewEncryptingStoreFromFile(dataStore DataStore, publicKeyFile string, opts EncryptionOptions) (DataStore, error) {
+	data, err := os.ReadFile(publicKeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read encryption key: %w", err)
+	}
+
+	recipient, err := report.ParseX25519PublicKey(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid encryption key: %w", err)
+	}
+
+	return NewEncryptingStore(dataStore, recipient, opts)
+}
+
+func (s encryptingStore) Store(ctx context.Context, data json.RawMessage) error {
+	encoding := CompressionNone
+	if len(data) >= s.opts.CompressionThreshold {
+		encoding = s.opts.Compression
+	}
+
+	sealed, err := report.Seal(data, s.recipient, s.opts.KeyID, encoding)
+	if err != nil {
+		return fmt.Errorf("failed to encrypt data: %w", err)
+	}
+
+	if s.opts.Observer != nil {
+		s.opts.Observer.Sealed(sealed, data)
+	}
+
+	return s.dataStore.Store(ctx, sealed)
+}
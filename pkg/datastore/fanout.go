@@ -0,0 +1,150 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+
+	"go.uber.org/zap"
+)
+
+// Sink is a named DataStore that is part of a fan-out.
+type Sink struct {
+	Name      string
+	DataStore DataStore
+	// Required sinks must accept the payload for the fan-out to succeed,
+	// failures of best-effort sinks are only logged.
+	Required bool
+}
+
+// SinkError is the failure of a single sink.
+type SinkError struct {
+	Sink     string
+	Required bool
+	Err      error
+}
+
+func (e *SinkError) Error() string {
+	return fmt.Sprintf("sink %s: %v", e.Sink, e.Err)
+}
+
+func (e *SinkError) Unwrap() error {
+	return e.Err
+}
+
+// FanOutError is returned when the payload could not be delivered to all
+// required sinks, or to no sink at all.
+type FanOutError struct {
+	Errors []*SinkError
+}
+
+func (e *FanOutError) Error() string {
+	msgs := make([]string, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		msgs = append(msgs, err.Error())
+	}
+
+	return fmt.Sprintf("failed to deliver data to %d sink(s): %s", len(e.Errors), strings.Join(msgs, "; "))
+}
+
+// Unwrap allows errors.As to find the errors of the individual sinks.
+func (e *FanOutError) Unwrap() []error {
+	errs := make([]error, 0, len(e.Errors))
+	for _, err := range e.Errors {
+		errs = append(errs, err)
+	}
+	return errs
+}
+
+type fanOut struct {
+	sinks []Sink
+	// parallel delivers to all sinks at once instead of one after another.
+	parallel bool
+	log      *zap.SugaredLogger
+}
+
+// NewFanOut returns a DataStore that delivers every payload to all sinks.
+func NewFanOut(sinks []Sink, parallel bool, log *zap.SugaredLogger) DataStore {
+	return fanOut{sinks: sinks, parallel: parallel, log: log}
+}
+
+func (f fanOut) Store(ctx context.Context, data json.RawMessage) error {
+	errs := make([]error, len(f.sinks))
+
+	if f.parallel {
+		var wg sync.WaitGroup
+		for i, sink := range f.sinks {
+			wg.Add(1)
+			go func(i int, sink Sink) {
+				defer wg.Done()
+				errs[i] = sink.DataStore.Store(ctx, data)
+			}(i, sink)
+		}
+		wg.Wait()
+	} else {
+		for i, sink := range f.sinks {
+			errs[i] = sink.DataStore.Store(ctx, data)
+		}
+	}
+
+	var (
+		failed         []*SinkError
+		delivered      int
+		requiredFailed bool
+		// permanent stays true if all failures of required sinks are
+		// permanent, so that retrying the whole fan-out is pointless
+		permanent = true
+	)
+	for i, sink := range f.sinks {
+		err := errs[i]
+		if err == nil {
+			delivered++
+			continue
+		}
+
+		if !sink.Required {
+			f.log.Warnw("Failed to deliver data to best-effort sink", "sink", sink.Name, "error", err)
+		}
+
+		failed = append(failed, &SinkError{Sink: sink.Name, Required: sink.Required, Err: err})
+
+		if sink.Required {
+			requiredFailed = true
+
+			var permanentErr *PermanentError
+			if !errors.As(err, &permanentErr) {
+				permanent = false
+			}
+		}
+	}
+
+	if !requiredFailed && delivered > 0 {
+		return nil
+	}
+
+	var err error = &FanOutError{Errors: failed}
+	if requiredFailed && permanent {
+		err = &PermanentError{Err: err}
+	}
+
+	return err
+}
@@ -30,11 +30,13 @@ import (
 
 type fileStore struct {
 	directory string
-	log       *zap.SugaredLogger
+	// compressed makes the store write gzip compressed .json.gz files.
+	compressed bool
+	log        *zap.SugaredLogger
 }
 
-func NewFileStore(directory string, log *zap.SugaredLogger) DataStore {
-	return fileStore{directory: directory, log: log}
+func NewFileStore(directory string, compressed bool, log *zap.SugaredLogger) DataStore {
+	return fileStore{directory: directory, compressed: compressed, log: log}
 }
 
 func (s fileStore) Store(ctx context.Context, data json.RawMessage) error {
@@ -49,6 +51,14 @@ func (s fileStore) Store(ctx context.Context, data json.RawMessage) error {
 	now := time.Now().UTC().Format("2006-01-02T15-04-05")
 	filename := filepath.Join(s.directory, fmt.Sprintf("record-%s-%s.json", now, rand.String(6)))
 
+	if s.compressed {
+		data, err = compress(data, CompressionGzip)
+		if err != nil {
+			return err
+		}
+		filename += ".gz"
+	}
+
 	f, err := os.Create(filename)
 	if err != nil {
 		return err
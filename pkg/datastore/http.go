@@ -19,35 +19,390 @@ package datastore
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
+	"crypto/x509"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net"
 	"net/http"
+	"net/url"
+	"os"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/kubermatic/telemetry-client/pkg/report"
 
 	"go.uber.org/zap"
 )
 
+const (
+	// DefaultMaxAttempts is the default number of times a report is sent
+	// before giving up.
+	DefaultMaxAttempts = 5
+	// DefaultAttemptTimeout is the default timeout for a single HTTP request.
+	DefaultAttemptTimeout = 30 * time.Second
+	// DefaultDeadline is the default time budget for all attempts combined.
+	DefaultDeadline = 5 * time.Minute
+
+	initialBackoff = 1 * time.Second
+	maxBackoff     = 1 * time.Minute
+
+	// maxErrorBody is the number of bytes of a failed response that are
+	// kept in the returned error.
+	maxErrorBody = 512
+)
+
+// HTTPOptions configures how reports are delivered to the HTTP collector.
+type HTTPOptions struct {
+	// MaxAttempts is the maximum number of requests made for a single
+	// report, including the first one.
+	MaxAttempts int
+	// AttemptTimeout bounds every single request.
+	AttemptTimeout time.Duration
+	// Deadline bounds all attempts including the time spent waiting
+	// between them.
+	Deadline time.Duration
+
+	// BearerTokenFile is read before every request and its content is sent
+	// as bearer token, so that a mounted Secret can be rotated.
+	BearerTokenFile string
+	// CAFile is a PEM bundle used instead of the system roots to verify
+	// the collector.
+	CAFile string
+	// ClientCertFile and ClientKeyFile are a PEM encoded certificate and key
+	// used to authenticate against the collector.
+	ClientCertFile string
+	ClientKeyFile  string
+	// ProxyURL overrides the proxy configured in the environment.
+	ProxyURL string
+	// Headers are added to every request.
+	Headers map[string]string
+	// Signer, if set, signs every payload and attaches the detached
+	// signature to the request headers. The signature covers the payload
+	// as passed to the store, i.e. the sealed envelope if it is encrypted.
+	Signer report.Signer
+	// Compression is the content coding used for payloads, one of
+	// CompressionNone, CompressionGzip or CompressionZstd.
+	Compression string
+	// CompressionThreshold is the minimum payload size in bytes that
+	// is compressed.
+	CompressionThreshold int
+}
+
+// StatusError is returned when the collector answers with a non-2xx status code.
+type StatusError struct {
+	URL        string
+	StatusCode int
+	Body       string
+	// RetryAfter is the delay the server asked for, if any.
+	RetryAfter time.Duration
+}
+
+func (e *StatusError) Error() string {
+	msg := fmt.Sprintf("%s responded with %d %s", e.URL, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Body != "" {
+		msg = fmt.Sprintf("%s: %s", msg, e.Body)
+	}
+	return msg
+}
+
+// Temporary returns true if sending the same request again might succeed.
+func (e *StatusError) Temporary() bool {
+	return e.StatusCode == http.StatusTooManyRequests ||
+		e.StatusCode == http.StatusRequestTimeout ||
+		e.StatusCode >= 500
+}
+
+// PermanentError wraps an error after which the request is not retried.
+type PermanentError struct {
+	Err error
+}
+
+func (e *PermanentError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *PermanentError) Unwrap() error {
+	return e.Err
+}
+
 type httpStore struct {
-	url string
-	log *zap.SugaredLogger
+	url    string
+	opts   HTTPOptions
+	client *http.Client
+	log    *zap.SugaredLogger
 }
 
-func NewHTTPStore(endpoint string, log *zap.SugaredLogger) DataStore {
-	return httpStore{url: endpoint, log: log}
+func NewHTTPStore(endpoint string, opts HTTPOptions, log *zap.SugaredLogger) (DataStore, error) {
+	if opts.MaxAttempts <= 0 {
+		opts.MaxAttempts = DefaultMaxAttempts
+	}
+	if opts.AttemptTimeout <= 0 {
+		opts.AttemptTimeout = DefaultAttemptTimeout
+	}
+	if opts.Deadline <= 0 {
+		opts.Deadline = DefaultDeadline
+	}
+
+	switch opts.Compression {
+	case CompressionNone, CompressionGzip, CompressionZstd:
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", opts.Compression)
+	}
+
+	client, err := newHTTPClient(opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return httpStore{url: endpoint, opts: opts, client: client, log: log}, nil
+}
+
+func newHTTPClient(opts HTTPOptions) (*http.Client, error) {
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+
+	if opts.ProxyURL != "" {
+		proxyURL, err := url.Parse(opts.ProxyURL)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy URL: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion: tls.VersionTLS12,
+	}
+
+	if opts.CAFile != "" {
+		caBundle, err := os.ReadFile(opts.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA bundle: %w", err)
+		}
+
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(caBundle) {
+			return nil, fmt.Errorf("no valid certificates found in %s", opts.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+
+	if opts.ClientCertFile != "" || opts.ClientKeyFile != "" {
+		if opts.ClientCertFile == "" || opts.ClientKeyFile == "" {
+			return nil, errors.New("both a client certificate and a client key are required")
+		}
+
+		cert, err := tls.LoadX509KeyPair(opts.ClientCertFile, opts.ClientKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
 }
 
 func (s httpStore) Store(ctx context.Context, data json.RawMessage) error {
-	req, err := http.NewRequestWithContext(ctx, "POST", s.url, bytes.NewBuffer(data))
+	_, err := s.storeWithStatus(ctx, data)
+	return err
+}
+
+// storeWithStatus is like Store, but also returns the status code of the
+// successful response.
+func (s httpStore) storeWithStatus(ctx context.Context, data json.RawMessage) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Deadline)
+	defer cancel()
+
+	encoding := CompressionNone
+	if len(data) >= s.opts.CompressionThreshold {
+		encoding = s.opts.Compression
+	}
+
+	body, err := compress(data, encoding)
 	if err != nil {
-		return err
+		return 0, fmt.Errorf("failed to compress data: %w", err)
+	}
+
+	for attempt := 1; ; attempt++ {
+		s.log.Infow("Sending data via HTTP…", "target", s.url, "attempt", attempt, "encoding", encoding, "size", len(body))
+
+		statusCode, err := s.send(ctx, data, body, encoding)
+		if err == nil {
+			return statusCode, nil
+		}
+
+		// the collector does not understand the compressed payload, so
+		// fall back to sending it as is
+		var statusErr *StatusError
+		if encoding != CompressionNone && errors.As(err, &statusErr) && statusErr.StatusCode == http.StatusUnsupportedMediaType {
+			s.log.Warnw("Collector does not accept compressed data, sending it uncompressed", "encoding", encoding)
+			encoding, body = CompressionNone, data
+			continue
+		}
+
+		var permanent *PermanentError
+		if errors.As(err, &permanent) || !isRetryable(err) {
+			return 0, err
+		}
+		if attempt >= s.opts.MaxAttempts {
+			return 0, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		}
+
+		wait := backoff(attempt)
+		if errors.As(err, &statusErr) && statusErr.RetryAfter > 0 {
+			wait = statusErr.RetryAfter
+		}
+
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			return 0, fmt.Errorf("giving up after %d attempts, next attempt would exceed the deadline: %w", attempt, err)
+		}
+
+		s.log.Warnw("Failed to send data, retrying", "attempt", attempt, "wait", wait, "error", err)
+
+		select {
+		case <-ctx.Done():
+			return 0, fmt.Errorf("giving up after %d attempts: %w", attempt, err)
+		case <-time.After(wait):
+		}
+	}
+}
+
+// send posts the body, which is the data encoded with the given content coding,
+// and returns the status code of a successful response.
+func (s httpStore) send(ctx context.Context, data json.RawMessage, body []byte, encoding string) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.AttemptTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.url, bytes.NewReader(body))
+	if err != nil {
+		return 0, &PermanentError{Err: err}
+	}
+	for name, value := range s.opts.Headers {
+		req.Header.Set(name, value)
 	}
 	req.Header.Set("Content-Type", "application/json")
+	if encoding != CompressionNone {
+		req.Header.Set("Content-Encoding", encoding)
+	}
 
-	s.log.Infow("Sending data via HTTP…", "target", s.url)
+	// the signature covers the uncompressed payload
+	if s.opts.Signer != nil {
+		sig, err := s.opts.Signer.Sign(data)
+		if err != nil {
+			return 0, &PermanentError{Err: fmt.Errorf("failed to sign payload: %w", err)}
+		}
+		sig.SetHeader(req.Header)
+	}
+
+	if s.opts.BearerTokenFile != "" {
+		token, err := os.ReadFile(s.opts.BearerTokenFile)
+		if err != nil {
+			return 0, &PermanentError{Err: fmt.Errorf("failed to read bearer token: %w", err)}
+		}
+		req.Header.Set("Authorization", "Bearer "+string(bytes.TrimSpace(token)))
+	}
 
-	resp, err := http.DefaultClient.Do(req)
+	resp, err := s.client.Do(req)
 	if err != nil {
-		return err
+		return 0, err
 	}
 	defer resp.Body.Close()
 
-	return nil
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		// drain the body so that the connection can be reused
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return resp.StatusCode, nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	statusErr := &StatusError{
+		URL:        s.url,
+		StatusCode: resp.StatusCode,
+		Body:       string(bytes.TrimSpace(respBody)),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	if !statusErr.Temporary() {
+		return 0, &PermanentError{Err: statusErr}
+	}
+
+	return 0, statusErr
+}
+
+// isRetryable decides whether a failed request is worth another attempt.
+func isRetryable(err error) bool {
+	var statusErr *StatusError
+	if errors.As(err, &statusErr) {
+		return statusErr.Temporary()
+	}
+
+	// the per-attempt timeout fired, but the overall deadline might still allow
+	// for another attempt; the caller checks the parent context
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	if errors.Is(err, context.Canceled) {
+		return false
+	}
+
+	// a misconfigured CA or client certificate will not fix itself
+	var certErr *tls.CertificateVerificationError
+	if errors.As(err, &certErr) {
+		return false
+	}
+
+	if errors.Is(err, syscall.ECONNRESET) ||
+		errors.Is(err, syscall.ECONNREFUSED) ||
+		errors.Is(err, syscall.ECONNABORTED) ||
+		errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, io.EOF) {
+		return true
+	}
+
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	var opErr *net.OpError
+	return errors.As(err, &opErr)
+}
+
+// backoff returns a randomized, exponentially growing delay for the given attempt.
+func backoff(attempt int) time.Duration {
+	d := initialBackoff << (attempt - 1)
+	if d <= 0 || d > maxBackoff {
+		d = maxBackoff
+	}
+
+	// "equal jitter": wait at least half of the delay, plus a random share of the other half
+	half := d / 2
+	return half + time.Duration(rand.Int63n(int64(half)+1))
+}
+
+// parseRetryAfter understands both forms of the Retry-After header,
+// delay-seconds and HTTP-date.
+func parseRetryAfter(value string) time.Duration {
+	if value == "" {
+		return 0
+	}
+
+	if seconds, err := strconv.Atoi(value); err == nil {
+		if seconds < 0 {
+			return 0
+		}
+		return time.Duration(seconds) * time.Second
+	}
+
+	if t, err := http.ParseTime(value); err == nil {
+		if d := time.Until(t); d > 0 {
+			return d
+		}
+	}
+
+	return 0
 }
@@ -0,0 +1,51 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// MemoryStore keeps all stored data in memory, e.g. to inspect the records
+// of agents without writing them anywhere.
+type MemoryStore struct {
+	lock sync.Mutex
+	data []json.RawMessage
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{}
+}
+
+func (m *MemoryStore) Store(ctx context.Context, data json.RawMessage) error {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	// the caller may reuse the buffer
+	m.data = append(m.data, append(json.RawMessage{}, data...))
+	return nil
+}
+
+// Data returns everything stored so far, in order.
+func (m *MemoryStore) Data() []json.RawMessage {
+	m.lock.Lock()
+	defer m.lock.Unlock()
+
+	return append([]json.RawMessage{}, m.data...)
+}
@@ -0,0 +1,351 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/kubermatic/telemetry-client/pkg/report"
+	v2 "github.com/kubermatic/telemetry-client/pkg/report/v2"
+	telemetryversion "github.com/kubermatic/telemetry-client/pkg/version"
+
+	commonpb "go.opentelemetry.io/proto/otlp/common/v1"
+	logspb "go.opentelemetry.io/proto/otlp/logs/v1"
+	metricspb "go.opentelemetry.io/proto/otlp/metrics/v1"
+	resourcepb "go.opentelemetry.io/proto/otlp/resource/v1"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// DefaultOTLPEndpoint is the OTLP/HTTP receiver of a local collector.
+	DefaultOTLPEndpoint = "http://localhost:4318"
+
+	otlpMetricsPath = "/v1/metrics"
+	otlpLogsPath    = "/v1/logs"
+
+	otlpScope       = "github.com/kubermatic/telemetry-client"
+	otlpServiceName = "kubermatic-telemetry"
+)
+
+// OTLPOptions configures the export to an OpenTelemetry collector.
+type OTLPOptions struct {
+	// Endpoint is the base URL of the OTLP/HTTP receiver, metrics and logs
+	// are sent to /v1/metrics and /v1/logs below it.
+	Endpoint string
+	// Timeout bounds every single request.
+	Timeout time.Duration
+	// Headers are added to every request, e.g. for authentication.
+	Headers map[string]string
+	// CAFile, ClientCertFile and ClientKeyFile configure TLS like for the
+	// HTTP datastore.
+	CAFile         string
+	ClientCertFile string
+	ClientKeyFile  string
+}
+
+type otlpStore struct {
+	endpoint string
+	opts     OTLPOptions
+	client   *http.Client
+	log      *zap.SugaredLogger
+}
+
+// NewOTLPStore returns a DataStore that translates reports into OTLP
+// metrics and logs and exports them via OTLP/HTTP using protobuf.
+func NewOTLPStore(opts OTLPOptions, log *zap.SugaredLogger) (DataStore, error) {
+	if opts.Endpoint == "" {
+		opts.Endpoint = DefaultOTLPEndpoint
+	}
+	if opts.Timeout <= 0 {
+		opts.Timeout = DefaultAttemptTimeout
+	}
+
+	client, err := newHTTPClient(HTTPOptions{
+		CAFile:         opts.CAFile,
+		ClientCertFile: opts.ClientCertFile,
+		ClientKeyFile:  opts.ClientKeyFile,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return otlpStore{
+		endpoint: strings.TrimSuffix(opts.Endpoint, "/"),
+		opts:     opts,
+		client:   client,
+		log:      log,
+	}, nil
+}
+
+func (s otlpStore) Store(ctx context.Context, data json.RawMessage) error {
+	if report.IsSealed(data) {
+		return &PermanentError{Err: errors.New("encrypted reports cannot be exported via OTLP")}
+	}
+
+	r, err := v2.ParseReport(data)
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+
+	records, err := r.DecodeRecords()
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+	if records.Skipped > 0 {
+		s.log.Warnw("Skipping records that cannot be exported", "count", records.Skipped)
+	}
+
+	resource := otlpResource(r, records)
+
+	metrics, err := proto.Marshal(&metricspb.MetricsData{
+		ResourceMetrics: []*metricspb.ResourceMetrics{{
+			Resource: resource,
+			ScopeMetrics: []*metricspb.ScopeMetrics{{
+				Scope:   otlpInstrumentationScope(),
+				Metrics: otlpMetrics(r.Time, records),
+			}},
+		}},
+	})
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to encode metrics: %w", err)}
+	}
+
+	logs, err := proto.Marshal(&logspb.LogsData{
+		ResourceLogs: []*logspb.ResourceLogs{{
+			Resource: resource,
+			ScopeLogs: []*logspb.ScopeLogs{{
+				Scope:      otlpInstrumentationScope(),
+				LogRecords: otlpLogRecords(r),
+			}},
+		}},
+	})
+	if err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to encode logs: %w", err)}
+	}
+
+	// MetricsData and LogsData are wire compatible with the export requests
+	// of the collector protocol
+	if err := s.export(ctx, otlpMetricsPath, metrics); err != nil {
+		return err
+	}
+
+	return s.export(ctx, otlpLogsPath, logs)
+}
+
+func (s otlpStore) export(ctx context.Context, path string, body []byte) error {
+	ctx, cancel := context.WithTimeout(ctx, s.opts.Timeout)
+	defer cancel()
+
+	target := s.endpoint + path
+
+	s.log.Infow("Exporting data via OTLP…", "target", target, "size", len(body))
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+	for name, value := range s.opts.Headers {
+		req.Header.Set(name, value)
+	}
+	req.Header.Set("Content-Type", "application/x-protobuf")
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 200 && resp.StatusCode < 300 {
+		_, _ = io.Copy(io.Discard, resp.Body)
+		return nil
+	}
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBody))
+	statusErr := &StatusError{
+		URL:        target,
+		StatusCode: resp.StatusCode,
+		Body:       string(bytes.TrimSpace(respBody)),
+		RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After")),
+	}
+	if !statusErr.Temporary() {
+		return &PermanentError{Err: statusErr}
+	}
+
+	return statusErr
+}
+
+func otlpResource(r *v2.Report, records *v2.Records) *resourcepb.Resource {
+	attributes := []*commonpb.KeyValue{
+		otlpString("service.name", otlpServiceName),
+		otlpString("telemetry.client_uuid", r.ClientUUID),
+	}
+
+	// a report contains at most one kubermatic record
+	if len(records.Kubermatic) > 0 {
+		attributes = append(attributes,
+			otlpString("kubermatic.version", records.Kubermatic[0].KubermaticVersion),
+			otlpString("kubermatic.edition", records.Kubermatic[0].KubermaticEdition),
+		)
+	}
+
+	return &resourcepb.Resource{Attributes: attributes}
+}
+
+func otlpInstrumentationScope() *commonpb.InstrumentationScope {
+	return &commonpb.InstrumentationScope{Name: otlpScope, Version: telemetryversion.Version}
+}
+
+// otlpMetrics aggregates the records into gauges.
+func otlpMetrics(now time.Time, records *v2.Records) []*metricspb.Metric {
+	nodes := labelCounter{}
+	for _, record := range records.Kubernetes {
+		for _, node := range record.Nodes {
+			nodes.inc(deref(node.Architecture), deref(node.OperatingSystem), deref(node.OSImage), deref(node.KubeletVersion))
+		}
+	}
+
+	var (
+		clusters         = labelCounter{}
+		features         = labelCounter{}
+		collectionErrors = labelCounter{}
+		seeds            int
+		projects         int
+		users            int
+	)
+	for _, record := range records.Kubermatic {
+		seeds += len(record.Seeds)
+		projects += len(record.Projects)
+		users += len(record.Users)
+
+		for _, collectionErr := range record.Errors {
+			collectionErrors.inc(collectionErr.Resource, collectionErr.Reason)
+		}
+
+		for _, cluster := range record.Clusters {
+			clusters.inc(cluster.Cloud.ProviderName, cluster.KubernetesServerVersion)
+
+			for feature, enabled := range map[string]bool{
+				"opa_integration":     cluster.OPAIntegrationEnabled,
+				"user_ssh_key_agent":  cluster.UserSSHKeyAgentEnabled,
+				"konnectivity":        cluster.ClusterNetwork.KonnectivityEnabled,
+				"mla_monitoring":      cluster.MLA.MonitoringEnabled,
+				"mla_logging":         cluster.MLA.LoggingEnabled,
+				"dual_stack_networks": cluster.ClusterNetwork.IPFamily == "IPv4+IPv6",
+			} {
+				if enabled {
+					features.inc(feature)
+				}
+			}
+		}
+	}
+
+	timestamp := uint64(now.UnixNano())
+
+	metrics := []*metricspb.Metric{
+		nodes.otlpGauge("kubernetes.nodes", "Number of nodes by architecture and operating system.", "{node}", timestamp,
+			"architecture", "operating_system", "os_image", "kubelet_version"),
+	}
+	if len(records.Kubermatic) > 0 {
+		metrics = append(metrics,
+			clusters.otlpGauge("kubermatic.clusters", "Number of user clusters by cloud provider and Kubernetes version.", "{cluster}", timestamp,
+				"provider", "kubernetes_version"),
+			features.otlpGauge("kubermatic.cluster.features", "Number of user clusters with a feature enabled.", "{cluster}", timestamp, "feature"),
+			otlpGauge("kubermatic.seeds", "Number of seed clusters.", "{seed}", otlpDataPoint(timestamp, seeds)),
+			otlpGauge("kubermatic.projects", "Number of projects.", "{project}", otlpDataPoint(timestamp, projects)),
+			otlpGauge("kubermatic.users", "Number of users.", "{user}", otlpDataPoint(timestamp, users)),
+			collectionErrors.otlpGauge("kubermatic.collection.errors", "Number of objects or resources the agent failed to collect.", "{error}", timestamp,
+				"resource", "reason"),
+		)
+	}
+
+	return metrics
+}
+
+// otlpLogRecords creates a log record for every record of the report.
+func otlpLogRecords(r *v2.Report) []*logspb.LogRecord {
+	observed := uint64(r.Time.UnixNano())
+
+	logRecords := make([]*logspb.LogRecord, 0, len(r.Records))
+	for _, raw := range r.Records {
+		var header struct {
+			Kind    string    `json:"kind"`
+			Version string    `json:"version"`
+			Time    time.Time `json:"time"`
+		}
+		_ = json.Unmarshal(raw, &header)
+
+		timestamp := observed
+		if !header.Time.IsZero() {
+			timestamp = uint64(header.Time.UnixNano())
+		}
+
+		logRecords = append(logRecords, &logspb.LogRecord{
+			TimeUnixNano:         timestamp,
+			ObservedTimeUnixNano: observed,
+			SeverityNumber:       logspb.SeverityNumber_SEVERITY_NUMBER_INFO,
+			SeverityText:         "INFO",
+			Body:                 &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: string(raw)}},
+			Attributes: []*commonpb.KeyValue{
+				otlpString("telemetry.record.kind", header.Kind),
+				otlpString("telemetry.record.version", header.Version),
+			},
+		})
+	}
+
+	return logRecords
+}
+
+func otlpGauge(name, description, unit string, dataPoints ...*metricspb.NumberDataPoint) *metricspb.Metric {
+	return &metricspb.Metric{
+		Name:        name,
+		Description: description,
+		Unit:        unit,
+		Data: &metricspb.Metric_Gauge{
+			Gauge: &metricspb.Gauge{DataPoints: dataPoints},
+		},
+	}
+}
+
+func otlpDataPoint(timestamp uint64, value int) *metricspb.NumberDataPoint {
+	return &metricspb.NumberDataPoint{
+		TimeUnixNano: timestamp,
+		Value:        &metricspb.NumberDataPoint_AsInt{AsInt: int64(value)},
+	}
+}
+
+func otlpString(key, value string) *commonpb.KeyValue {
+	return &commonpb.KeyValue{
+		Key:   key,
+		Value: &commonpb.AnyValue{Value: &commonpb.AnyValue_StringValue{StringValue: value}},
+	}
+}
+
+func deref(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
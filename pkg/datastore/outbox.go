@@ -0,0 +1,234 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+const (
+	outboxPrefix = "outbox-"
+	outboxSuffix = ".json"
+)
+
+// outbox keeps payloads that could not be delivered on disk and replays
+// them, oldest first, the next time data is stored.
+type outbox struct {
+	dataStore DataStore
+	directory string
+	// maxSize is the maximum number of bytes kept in the outbox, 0 means unlimited.
+	maxSize int64
+	// maxAge is the maximum age of a kept payload, 0 means unlimited.
+	maxAge time.Duration
+	log    *zap.SugaredLogger
+}
+
+type outboxEntry struct {
+	path    string
+	size    int64
+	modTime time.Time
+}
+
+// NewOutbox wraps the given DataStore so that payloads which cannot be
+// delivered are persisted in directory instead of being lost.
+func NewOutbox(dataStore DataStore, directory string, maxSize int64, maxAge time.Duration, log *zap.SugaredLogger) DataStore {
+	return outbox{
+		dataStore: dataStore,
+		directory: directory,
+		maxSize:   maxSize,
+		maxAge:    maxAge,
+		log:       log,
+	}
+}
+
+func (o outbox) Store(ctx context.Context, data json.RawMessage) error {
+	if err := os.MkdirAll(o.directory, 0o700); err != nil {
+		return fmt.Errorf("failed to create outbox directory: %w", err)
+	}
+
+	if err := o.evict(); err != nil {
+		return err
+	}
+
+	delivered, err := o.replay(ctx)
+	if err != nil {
+		return err
+	}
+
+	// there is no point in trying to send the new payload when the pending
+	// ones could not be delivered, and it would break the ordering
+	if delivered {
+		err := o.dataStore.Store(ctx, data)
+		if err == nil {
+			return nil
+		}
+
+		var permanent *PermanentError
+		if errors.As(err, &permanent) {
+			return err
+		}
+
+		o.log.Warnw("Failed to deliver data, keeping it in the outbox", "error", err)
+	}
+
+	if err := o.spool(data); err != nil {
+		return err
+	}
+
+	return o.evict()
+}
+
+// replay sends all pending payloads oldest-first. It returns false if a
+// payload could not be delivered and must be kept for the next run.
+func (o outbox) replay(ctx context.Context) (bool, error) {
+	entries, err := o.entries()
+	if err != nil {
+		return false, err
+	}
+
+	for _, entry := range entries {
+		data, err := os.ReadFile(entry.path)
+		if err != nil {
+			return false, err
+		}
+
+		o.log.Infow("Replaying data from outbox", "filename", entry.path)
+
+		if err := o.dataStore.Store(ctx, data); err != nil {
+			var permanent *PermanentError
+			if !errors.As(err, &permanent) {
+				o.log.Warnw("Failed to replay data from outbox", "filename", entry.path, "error", err)
+				return false, nil
+			}
+
+			o.log.Errorw("Discarding data that was rejected permanently", "filename", entry.path, "error", err)
+		}
+
+		if err := os.Remove(entry.path); err != nil {
+			return false, err
+		}
+	}
+
+	return true, nil
+}
+
+// spool writes the payload atomically into the outbox.
+func (o outbox) spool(data json.RawMessage) error {
+	now := time.Now().UTC().Format("2006-01-02T15-04-05.000000000")
+	filename := filepath.Join(o.directory, fmt.Sprintf("%s%s-%s%s", outboxPrefix, now, rand.String(6), outboxSuffix))
+
+	tmp, err := os.CreateTemp(o.directory, ".tmp-")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmp.Name(), filename); err != nil {
+		return err
+	}
+
+	o.log.Infow("Stored data in outbox", "filename", filename)
+
+	return nil
+}
+
+// evict removes payloads that are older than maxAge and, oldest first,
+// as many payloads as needed to stay within maxSize.
+func (o outbox) evict() error {
+	entries, err := o.entries()
+	if err != nil {
+		return err
+	}
+
+	var total int64
+	for _, entry := range entries {
+		total += entry.size
+	}
+
+	for _, entry := range entries {
+		expired := o.maxAge > 0 && time.Since(entry.modTime) > o.maxAge
+		oversized := o.maxSize > 0 && total > o.maxSize
+		if !expired && !oversized {
+			continue
+		}
+
+		o.log.Warnw("Evicting data from outbox", "filename", entry.path, "expired", expired, "oversized", oversized)
+
+		if err := os.Remove(entry.path); err != nil && !os.IsNotExist(err) {
+			return err
+		}
+		total -= entry.size
+	}
+
+	return nil
+}
+
+// entries returns all pending payloads, oldest first.
+func (o outbox) entries() ([]outboxEntry, error) {
+	dirEntries, err := os.ReadDir(o.directory)
+	if err != nil {
+		return nil, err
+	}
+
+	entries := []outboxEntry{}
+	for _, e := range dirEntries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), outboxPrefix) || !strings.HasSuffix(e.Name(), outboxSuffix) {
+			continue
+		}
+
+		info, err := e.Info()
+		if err != nil {
+			return nil, err
+		}
+
+		entries = append(entries, outboxEntry{
+			path:    filepath.Join(o.directory, e.Name()),
+			size:    info.Size(),
+			modTime: info.ModTime(),
+		})
+	}
+
+	// filenames start with a sortable timestamp
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[i].path < entries[j].path
+	})
+
+	return entries, nil
+}
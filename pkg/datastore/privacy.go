@@ -0,0 +1,47 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	"github.com/kubermatic/telemetry-client/pkg/privacy"
+)
+
+// privacyFilter applies a privacy policy to every record before passing
+// it on, so that filtered fields never reach the disk.
+type privacyFilter struct {
+	dataStore  DataStore
+	policy     *privacy.Policy
+	anonymizer agent.Anonymizer
+}
+
+func NewPrivacyFilter(dataStore DataStore, policy *privacy.Policy, anonymizer agent.Anonymizer) DataStore {
+	return privacyFilter{dataStore: dataStore, policy: policy, anonymizer: anonymizer}
+}
+
+func (s privacyFilter) Store(ctx context.Context, data json.RawMessage) error {
+	filtered, err := s.policy.Apply(data, s.anonymizer)
+	if err != nil {
+		return fmt.Errorf("failed to apply privacy policy: %w", err)
+	}
+
+	return s.dataStore.Store(ctx, filtered)
+}
@@ -0,0 +1,248 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/kubermatic/telemetry-client/pkg/report"
+	v2 "github.com/kubermatic/telemetry-client/pkg/report/v2"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/push"
+	"go.uber.org/zap"
+)
+
+const (
+	// DefaultPrometheusJob is the job label used when pushing to a Pushgateway.
+	DefaultPrometheusJob = "kubermatic_telemetry"
+
+	prometheusNamespace = "telemetry"
+)
+
+var (
+	nodesDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubernetes", "nodes"),
+		"Number of nodes by OS image and kubelet version.",
+		[]string{"os_image", "kubelet_version", "architecture", "container_runtime_version"}, nil,
+	)
+	kubermaticInfoDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubermatic", "info"),
+		"Version and edition of Kubermatic, always 1.",
+		[]string{"version", "edition"}, nil,
+	)
+	seedsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubermatic", "seeds"),
+		"Number of seeds by expose strategy.",
+		[]string{"expose_strategy"}, nil,
+	)
+	clustersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubermatic", "clusters"),
+		"Number of user clusters by cloud provider, CNI plugin and expose strategy.",
+		[]string{"provider", "cni_plugin", "cni_plugin_version", "expose_strategy"}, nil,
+	)
+	projectsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubermatic", "projects"),
+		"Number of projects.",
+		nil, nil,
+	)
+	usersDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubermatic", "users"),
+		"Number of users.",
+		nil, nil,
+	)
+	adminsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubermatic", "admins"),
+		"Number of users with the admin role.",
+		nil, nil,
+	)
+	sshKeysDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubermatic", "ssh_keys"),
+		"Number of user SSH keys.",
+		nil, nil,
+	)
+	collectionErrorsDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "kubermatic", "collection_errors"),
+		"Number of objects or resources the agent failed to collect, by resource and reason.",
+		[]string{"resource", "reason"}, nil,
+	)
+	lastReportDesc = prometheus.NewDesc(
+		prometheus.BuildFQName(prometheusNamespace, "", "last_report_timestamp_seconds"),
+		"Time of the latest report, as Unix timestamp.",
+		nil, nil,
+	)
+)
+
+// prometheusStore keeps the metrics of the latest report, so that they can
+// be scraped or pushed to a Pushgateway.
+type prometheusStore struct {
+	// pusher is nil if metrics are not pushed.
+	pusher *push.Pusher
+	log    *zap.SugaredLogger
+
+	lock    sync.RWMutex
+	metrics []prometheus.Metric
+}
+
+var _ prometheus.Collector = &prometheusStore{}
+
+// NewPrometheusStore returns a DataStore that translates reports into
+// Prometheus gauges. The gauges are registered with the given Registerer and,
+// if pushURL is not empty, pushed to the Pushgateway after every report.
+func NewPrometheusStore(registerer prometheus.Registerer, pushURL, job string, log *zap.SugaredLogger) (DataStore, error) {
+	s := &prometheusStore{log: log}
+
+	if err := registerer.Register(s); err != nil {
+		return nil, fmt.Errorf("failed to register metrics: %w", err)
+	}
+
+	if pushURL != "" {
+		if job == "" {
+			job = DefaultPrometheusJob
+		}
+
+		// the store is the only collector pushed, so that the metrics of the
+		// reporter itself do not end up in the Pushgateway
+		s.pusher = push.New(pushURL, job).Collector(s)
+	}
+
+	return s, nil
+}
+
+func (s *prometheusStore) Store(ctx context.Context, data json.RawMessage) error {
+	if report.IsSealed(data) {
+		return &PermanentError{Err: errors.New("encrypted reports cannot be exported as metrics")}
+	}
+
+	r, err := v2.ParseReport(data)
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+
+	records, err := r.DecodeRecords()
+	if err != nil {
+		return &PermanentError{Err: err}
+	}
+	if records.Skipped > 0 {
+		s.log.Warnw("Skipping records that cannot be exported", "count", records.Skipped)
+	}
+
+	metrics := []prometheus.Metric{
+		prometheus.MustNewConstMetric(lastReportDesc, prometheus.GaugeValue, float64(r.Time.Unix())),
+	}
+	metrics = append(metrics, kubernetesMetrics(records)...)
+	metrics = append(metrics, kubermaticMetrics(records)...)
+
+	s.lock.Lock()
+	s.metrics = metrics
+	s.lock.Unlock()
+
+	s.log.Infow("Updated metrics", "metrics", len(metrics))
+
+	if s.pusher == nil {
+		return nil
+	}
+
+	s.log.Infow("Pushing metrics…")
+
+	// replace all metrics of the job, so that label combinations which are
+	// gone are removed as well
+	if err := s.pusher.PushContext(ctx); err != nil {
+		return fmt.Errorf("failed to push metrics: %w", err)
+	}
+
+	s.log.Infow("Pushed metrics")
+
+	return nil
+}
+
+func (s *prometheusStore) Describe(ch chan<- *prometheus.Desc) {
+	for _, desc := range []*prometheus.Desc{
+		nodesDesc, kubermaticInfoDesc, seedsDesc, clustersDesc,
+		projectsDesc, usersDesc, adminsDesc, sshKeysDesc, collectionErrorsDesc, lastReportDesc,
+	} {
+		ch <- desc
+	}
+}
+
+func (s *prometheusStore) Collect(ch chan<- prometheus.Metric) {
+	s.lock.RLock()
+	defer s.lock.RUnlock()
+
+	for _, metric := range s.metrics {
+		ch <- metric
+	}
+}
+
+func kubernetesMetrics(records *v2.Records) []prometheus.Metric {
+	nodes := labelCounter{}
+	for _, record := range records.Kubernetes {
+		for _, node := range record.Nodes {
+			nodes.inc(deref(node.OSImage), deref(node.KubeletVersion), deref(node.Architecture), deref(node.ContainerRuntimeVersion))
+		}
+	}
+
+	return nodes.metrics(nodesDesc)
+}
+
+func kubermaticMetrics(records *v2.Records) []prometheus.Metric {
+	// a report contains at most one kubermatic record, and the registry
+	// rejects duplicate series
+	if len(records.Kubermatic) == 0 {
+		return nil
+	}
+	record := records.Kubermatic[0]
+
+	seeds := labelCounter{}
+	for _, seed := range record.Seeds {
+		seeds.inc(seed.ExposeStrategy)
+	}
+
+	clusters := labelCounter{}
+	for _, cluster := range record.Clusters {
+		clusters.inc(cluster.Cloud.ProviderName, cluster.CNIPlugin.Type, cluster.CNIPlugin.Version, cluster.ExposeStrategy)
+	}
+
+	collectionErrors := labelCounter{}
+	for _, collectionErr := range record.Errors {
+		collectionErrors.inc(collectionErr.Resource, collectionErr.Reason)
+	}
+
+	admins := 0
+	for _, user := range record.Users {
+		if user.IsAdmin {
+			admins++
+		}
+	}
+
+	metrics := []prometheus.Metric{
+		prometheus.MustNewConstMetric(kubermaticInfoDesc, prometheus.GaugeValue, 1, record.KubermaticVersion, record.KubermaticEdition),
+		prometheus.MustNewConstMetric(projectsDesc, prometheus.GaugeValue, float64(len(record.Projects))),
+		prometheus.MustNewConstMetric(usersDesc, prometheus.GaugeValue, float64(len(record.Users))),
+		prometheus.MustNewConstMetric(adminsDesc, prometheus.GaugeValue, float64(admins)),
+		prometheus.MustNewConstMetric(sshKeysDesc, prometheus.GaugeValue, float64(len(record.SSHKeys))),
+	}
+	metrics = append(metrics, seeds.metrics(seedsDesc)...)
+	metrics = append(metrics, clusters.metrics(clustersDesc)...)
+	metrics = append(metrics, collectionErrors.metrics(collectionErrorsDesc)...)
+
+	return metrics
+}
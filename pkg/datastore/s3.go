@@ -0,0 +1,180 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/minio/minio-go/v7/pkg/credentials"
+	"go.uber.org/zap"
+	"k8s.io/apimachinery/pkg/util/rand"
+)
+
+// DefaultS3KeyPrefix stores reports grouped by day and client.
+const DefaultS3KeyPrefix = "telemetry/{{ .Date }}/{{ .ClientUUID }}"
+
+// S3Options configures where reports are stored in an S3-compatible bucket.
+type S3Options struct {
+	// Endpoint is the host[:port] of the S3 API, e.g. s3.amazonaws.com.
+	Endpoint string
+	// Insecure uses plain HTTP instead of HTTPS.
+	Insecure bool
+	// PathStyle forces path-style instead of virtual-host-style bucket
+	// addressing, which most self-hosted implementations require.
+	PathStyle bool
+	Region    string
+	Bucket    string
+	// KeyPrefix is a text/template for the object key prefix. It can use
+	// .ClientUUID, .Date (2006-01-02), .Year, .Month and .Day.
+	KeyPrefix string
+	// ClientUUID is the UUID of the reporting installation.
+	ClientUUID string
+
+	// AccessKeyID and the content of SecretAccessKeyFile are static
+	// credentials. The secret is read from a file, so that it does not
+	// show up in the process arguments.
+	AccessKeyID         string
+	SecretAccessKeyFile string
+	// CredentialsFile is an AWS shared credentials file, used if no static
+	// credentials are given. Profile selects the profile within it.
+	CredentialsFile string
+	Profile         string
+}
+
+type s3KeyData struct {
+	ClientUUID string
+	Date       string
+	Year       string
+	Month      string
+	Day        string
+}
+
+type s3Store struct {
+	client     *minio.Client
+	bucket     string
+	keyPrefix  *template.Template
+	clientUUID string
+	log        *zap.SugaredLogger
+}
+
+func NewS3Store(opts S3Options, log *zap.SugaredLogger) (DataStore, error) {
+	if opts.Endpoint == "" {
+		return nil, errors.New("no S3 endpoint given")
+	}
+	if opts.Bucket == "" {
+		return nil, errors.New("no S3 bucket given")
+	}
+
+	if opts.KeyPrefix == "" {
+		opts.KeyPrefix = DefaultS3KeyPrefix
+	}
+	keyPrefix, err := template.New("key-prefix").Option("missingkey=error").Parse(opts.KeyPrefix)
+	if err != nil {
+		return nil, fmt.Errorf("invalid key prefix: %w", err)
+	}
+
+	var creds *credentials.Credentials
+	switch {
+	case opts.AccessKeyID != "":
+		if opts.SecretAccessKeyFile == "" {
+			return nil, errors.New("no secret access key file given for the access key ID")
+		}
+		secret, err := os.ReadFile(opts.SecretAccessKeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read secret access key: %w", err)
+		}
+		creds = credentials.NewStaticV4(opts.AccessKeyID, string(bytes.TrimSpace(secret)), "")
+	case opts.CredentialsFile != "":
+		creds = credentials.NewFileAWSCredentials(opts.CredentialsFile, opts.Profile)
+	default:
+		creds = credentials.NewChainCredentials([]credentials.Provider{
+			&credentials.EnvAWS{},
+			&credentials.EnvMinio{},
+		})
+	}
+
+	lookup := minio.BucketLookupAuto
+	if opts.PathStyle {
+		lookup = minio.BucketLookupPath
+	}
+
+	client, err := minio.New(opts.Endpoint, &minio.Options{
+		Creds:        creds,
+		Secure:       !opts.Insecure,
+		Region:       opts.Region,
+		BucketLookup: lookup,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to create S3 client: %w", err)
+	}
+
+	return s3Store{
+		client:     client,
+		bucket:     opts.Bucket,
+		keyPrefix:  keyPrefix,
+		clientUUID: opts.ClientUUID,
+		log:        log,
+	}, nil
+}
+
+func (s s3Store) Store(ctx context.Context, data json.RawMessage) error {
+	now := time.Now().UTC()
+
+	var prefix strings.Builder
+	if err := s.keyPrefix.Execute(&prefix, s3KeyData{
+		ClientUUID: s.clientUUID,
+		Date:       now.Format("2006-01-02"),
+		Year:       now.Format("2006"),
+		Month:      now.Format("01"),
+		Day:        now.Format("02"),
+	}); err != nil {
+		return &PermanentError{Err: fmt.Errorf("failed to render key prefix: %w", err)}
+	}
+
+	key := path.Join(prefix.String(), fmt.Sprintf("report-%s-%s.json", now.Format("2006-01-02T15-04-05"), rand.String(6)))
+
+	s.log.Infow("Uploading data to S3…", "bucket", s.bucket, "key", key)
+
+	_, err := s.client.PutObject(ctx, s.bucket, key, bytes.NewReader(data), int64(len(data)), minio.PutObjectOptions{
+		ContentType: "application/json",
+	})
+	if err != nil {
+		// the client already retried transient failures, anything in the
+		// 4xx range besides throttling will not succeed on the next run either
+		resp := minio.ToErrorResponse(err)
+		if resp.StatusCode >= 400 && resp.StatusCode < 500 &&
+			resp.StatusCode != http.StatusTooManyRequests && resp.StatusCode != http.StatusRequestTimeout {
+			return &PermanentError{Err: err}
+		}
+		return err
+	}
+
+	s.log.Infow("Uploaded data to S3", "bucket", s.bucket, "key", key)
+
+	return nil
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package datastore
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+
+	"go.uber.org/zap"
+)
+
+// s3StandIn answers PutObject requests like an S3 API with path-style
+// addressing and keeps the uploaded objects.
+type s3StandIn struct {
+	status int
+
+	lock    sync.Mutex
+	objects map[string]string
+	auth    []string
+}
+
+func (s *s3StandIn) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPut {
+		w.WriteHeader(http.StatusNotImplemented)
+		return
+	}
+
+	var body []byte
+	var err error
+	if r.Header.Get("X-Amz-Content-Sha256") == "STREAMING-AWS4-HMAC-SHA256-PAYLOAD" {
+		body, err = readChunkedPayload(r.Body)
+	} else {
+		body, err = io.ReadAll(r.Body)
+	}
+	if err != nil {
+		w.WriteHeader(http.StatusBadRequest)
+		return
+	}
+
+	s.lock.Lock()
+	s.auth = append(s.auth, r.Header.Get("Authorization"))
+	s.lock.Unlock()
+
+	if s.status != http.StatusOK {
+		w.Header().Set("Content-Type", "application/xml")
+		w.WriteHeader(s.status)
+		_, _ = io.WriteString(w, `<?xml version="1.0" encoding="UTF-8"?><Error><Code>AccessDenied</Code><Message>Access Denied</Message></Error>`)
+		return
+	}
+
+	s.lock.Lock()
+	s.objects[r.URL.Path] = string(body)
+	s.lock.Unlock()
+
+	w.Header().Set("ETag", `"d41d8cd98f00b204e9800998ecf8427e"`)
+	w.WriteHeader(http.StatusOK)
+}
+
+// readChunkedPayload decodes a body uploaded with chunked signatures, which
+// the client uses over plain HTTP.
+func readChunkedPayload(r io.Reader) ([]byte, error) {
+	var payload []byte
+	reader := bufio.NewReader(r)
+	for {
+		header, err := reader.ReadString('\n')
+		if err != nil {
+			return nil, err
+		}
+
+		sizeHex, _, _ := strings.Cut(strings.TrimSpace(header), ";")
+		size, err := strconv.ParseInt(sizeHex, 16, 64)
+		if err != nil {
+			return nil, err
+		}
+
+		// every chunk, including the final empty one, ends with CRLF
+		chunk := make([]byte, size+2)
+		if _, err := io.ReadFull(reader, chunk); err != nil {
+			return nil, err
+		}
+		if size == 0 {
+			return payload, nil
+		}
+		payload = append(payload, chunk[:size]...)
+	}
+}
+
+func newTestS3Store(t *testing.T, standIn *s3StandIn) DataStore {
+	t.Helper()
+
+	server := httptest.NewServer(standIn)
+	t.Cleanup(server.Close)
+
+	secretFile := filepath.Join(t.TempDir(), "secret")
+	if err := os.WriteFile(secretFile, []byte("secret\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	store, err := NewS3Store(S3Options{
+		Endpoint:            strings.TrimPrefix(server.URL, "http://"),
+		Insecure:            true,
+		PathStyle:           true,
+		Region:              "us-east-1",
+		Bucket:              "reports",
+		KeyPrefix:           "telemetry/{{ .ClientUUID }}",
+		ClientUUID:          "client",
+		AccessKeyID:         "access",
+		SecretAccessKeyFile: secretFile,
+	}, zap.NewNop().Sugar())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return store
+}
+
+func TestS3Store(t *testing.T) {
+	standIn := &s3StandIn{status: http.StatusOK, objects: map[string]string{}}
+	store := newTestS3Store(t, standIn)
+
+	if err := store.Store(context.Background(), []byte(`{"client_uuid":"client"}`)); err != nil {
+		t.Fatalf("failed to store report: %v", err)
+	}
+
+	if len(standIn.objects) != 1 {
+		t.Fatalf("expected one object, got %d", len(standIn.objects))
+	}
+	for key, body := range standIn.objects {
+		if !strings.HasPrefix(key, "/reports/telemetry/client/report-") || !strings.HasSuffix(key, ".json") {
+			t.Errorf("unexpected object key %q", key)
+		}
+		if body != `{"client_uuid":"client"}` {
+			t.Errorf("unexpected object content %q", body)
+		}
+	}
+
+	if !strings.Contains(standIn.auth[0], "Credential=access/") {
+		t.Errorf("request not signed with the static access key: %q", standIn.auth[0])
+	}
+}
+
+func TestS3StoreRejected(t *testing.T) {
+	standIn := &s3StandIn{status: http.StatusForbidden, objects: map[string]string{}}
+	store := newTestS3Store(t, standIn)
+
+	err := store.Store(context.Background(), []byte(`{}`))
+
+	var permanent *PermanentError
+	if !errors.As(err, &permanent) {
+		t.Fatalf("expected a permanent error, got %v", err)
+	}
+	if len(standIn.objects) != 0 {
+		t.Errorf("expected no objects, got %d", len(standIn.objects))
+	}
+}
+
+func TestS3StoreMissingSecret(t *testing.T) {
+	_, err := NewS3Store(S3Options{
+		Endpoint:    "s3.example.com",
+		Bucket:      "reports",
+		AccessKeyID: "access",
+	}, zap.NewNop().Sugar())
+	if err == nil {
+		t.Fatal("expected an error without a secret access key file")
+	}
+}
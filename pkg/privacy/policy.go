@@ -0,0 +1,341 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privacy
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"os"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	kubermatictypes "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2/types"
+	kubernetestypes "github.com/kubermatic/telemetry-client/pkg/agent/kubernetes/v2/types"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Action is what a Rule does with a field.
+type Action string
+
+const (
+	// ActionDrop removes the field.
+	ActionDrop Action = "drop"
+	// ActionHash replaces the value with an anonymized identifier, so that
+	// values can still be compared without being revealed.
+	ActionHash Action = "hash"
+	// ActionTruncate shortens the value. IP addresses are reduced to their
+	// network prefix, other values to their first characters.
+	ActionTruncate Action = "truncate"
+	// ActionCoarsen reduces the precision of versions, e.g. v1.29.3 to v1.29.
+	ActionCoarsen Action = "coarsen"
+)
+
+const (
+	defaultIPv4Prefix    = 24
+	defaultIPv6Prefix    = 48
+	defaultVersionParts  = 2
+	defaultTruncateChars = 8
+)
+
+// recordTypes are the records a policy can be applied to, by kind.
+var recordTypes = map[string]reflect.Type{
+	agent.KindKubernetes: reflect.TypeOf(kubernetestypes.Record{}),
+	agent.KindKubermatic: reflect.TypeOf(kubermatictypes.Record{}),
+}
+
+// versionPattern matches the numeric part of a version.
+var versionPattern = regexp.MustCompile(`\d+(\.\d+)*`)
+
+// Policy controls which fields of the records are reported.
+type Policy struct {
+	// Preset is a built-in policy the rules are added to.
+	Preset string `json:"preset,omitempty"`
+	Rules  []Rule `json:"rules,omitempty"`
+}
+
+// Rule applies an action to a field of all records of a kind.
+type Rule struct {
+	// Kind is the kind of record, kubernetes or kubermatic.
+	Kind string `json:"kind"`
+	// Field is the path of the field using the JSON names, e.g.
+	// nodes.external_ip. Lists are traversed, so the rule applies to the
+	// field of every list item.
+	Field  string `json:"field"`
+	Action Action `json:"action"`
+
+	// IPv4Prefix and IPv6Prefix are the prefix lengths IP addresses are
+	// truncated to, by default 24 and 48.
+	IPv4Prefix int `json:"ipv4Prefix,omitempty"`
+	IPv6Prefix int `json:"ipv6Prefix,omitempty"`
+	// Length is the number of characters other values are truncated to,
+	// by default 8.
+	Length int `json:"length,omitempty"`
+	// Parts is the number of version components kept when coarsening, by
+	// default 2.
+	Parts int `json:"parts,omitempty"`
+}
+
+// Load returns the policy for the preset with the rules from the policy
+// file added, if one is given. A preset given in the file takes
+// precedence over the preset argument. If maxPreset is not empty, e.g.
+// because of the consent level, a more permissive preset is replaced by it.
+func Load(preset, policyFile, maxPreset string) (*Policy, error) {
+	policy := &Policy{}
+	if policyFile != "" {
+		data, err := os.ReadFile(policyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read privacy policy: %w", err)
+		}
+		if err := yaml.UnmarshalStrict(data, policy); err != nil {
+			return nil, fmt.Errorf("failed to decode privacy policy: %w", err)
+		}
+	}
+	if policy.Preset == "" {
+		policy.Preset = preset
+	}
+
+	for _, name := range []string{policy.Preset, maxPreset} {
+		if name != "" && !IsPreset(name) {
+			return nil, fmt.Errorf("unknown privacy preset %q, must be one of %s", name, strings.Join(Presets(), ", "))
+		}
+	}
+	if maxPreset != "" {
+		policy.Preset = MoreRestrictive(policy.Preset, maxPreset)
+	}
+
+	// rules of the file are applied after the ones of the preset
+	policy.Rules = append(append([]Rule{}, presets[policy.Preset]...), policy.Rules...)
+
+	if err := policy.Validate(); err != nil {
+		return nil, err
+	}
+
+	return policy, nil
+}
+
+// Validate checks that all rules refer to existing fields.
+func (p *Policy) Validate() error {
+	var errs []error
+	for i, rule := range p.Rules {
+		if err := rule.validate(); err != nil {
+			errs = append(errs, fmt.Errorf("rule %d: %w", i+1, err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+func (r Rule) validate() error {
+	recordType, ok := recordTypes[r.Kind]
+	if !ok {
+		return fmt.Errorf("unknown record kind %q", r.Kind)
+	}
+
+	switch r.Action {
+	case ActionDrop, ActionHash, ActionTruncate, ActionCoarsen:
+	default:
+		return fmt.Errorf("unknown action %q", r.Action)
+	}
+
+	if r.IPv4Prefix < 0 || r.IPv4Prefix > 32 || r.IPv6Prefix < 0 || r.IPv6Prefix > 128 {
+		return errors.New("invalid IP prefix length")
+	}
+	if r.Length < 0 || r.Parts < 0 {
+		return errors.New("length and parts must not be negative")
+	}
+
+	path := strings.Split(r.Field, ".")
+	switch path[0] {
+	case "kind", "version", "time":
+		// the records cannot be processed anymore without them
+		return fmt.Errorf("field %q cannot be changed", r.Field)
+	}
+
+	t := recordType
+	for _, name := range path {
+		field, ok := jsonField(t, name)
+		if !ok {
+			return fmt.Errorf("unknown field %q for %s records", r.Field, r.Kind)
+		}
+
+		t = field.Type
+		for t.Kind() == reflect.Pointer || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+	}
+
+	return nil
+}
+
+// jsonField finds the struct field with the given JSON name, including
+// fields of embedded structs.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	if t.Kind() != reflect.Struct {
+		return reflect.StructField{}, false
+	}
+
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous {
+			if embedded, ok := jsonField(field.Type, name); ok {
+				return embedded, true
+			}
+			continue
+		}
+
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == name {
+			return field, true
+		}
+	}
+
+	return reflect.StructField{}, false
+}
+
+// Apply returns the record with all rules for its kind applied. Records
+// without any rules are returned unchanged.
+func (p *Policy) Apply(record json.RawMessage, anonymizer agent.Anonymizer) (json.RawMessage, error) {
+	var kv agent.KindVersion
+	if err := json.Unmarshal(record, &kv); err != nil {
+		return nil, fmt.Errorf("failed to decode record: %w", err)
+	}
+
+	var rules []Rule
+	for _, rule := range p.Rules {
+		if rule.Kind == kv.Kind {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return record, nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(record))
+	// keep numbers exactly as they are
+	decoder.UseNumber()
+
+	var doc map[string]any
+	if err := decoder.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("failed to decode record: %w", err)
+	}
+
+	for _, rule := range rules {
+		rule.apply(doc, strings.Split(rule.Field, "."), anonymizer)
+	}
+
+	return json.Marshal(doc)
+}
+
+func (r Rule) apply(value any, path []string, anonymizer agent.Anonymizer) {
+	switch v := value.(type) {
+	case []any:
+		for _, item := range v {
+			r.apply(item, path, anonymizer)
+		}
+
+	case map[string]any:
+		field, ok := v[path[0]]
+		if !ok {
+			return
+		}
+
+		if len(path) > 1 {
+			r.apply(field, path[1:], anonymizer)
+			return
+		}
+
+		if r.Action == ActionDrop {
+			delete(v, path[0])
+			return
+		}
+
+		v[path[0]] = r.transform(field, anonymizer)
+	}
+}
+
+// transform applies the action to a single value. Lists are transformed
+// item by item, other values than strings are left as they are.
+func (r Rule) transform(value any, anonymizer agent.Anonymizer) any {
+	if list, ok := value.([]any); ok {
+		for i, item := range list {
+			list[i] = r.transform(item, anonymizer)
+		}
+		return list
+	}
+
+	s, ok := value.(string)
+	if !ok || s == "" {
+		return value
+	}
+
+	switch r.Action {
+	case ActionHash:
+		return anonymizer.ID(s)
+	case ActionTruncate:
+		return r.truncate(s)
+	case ActionCoarsen:
+		return r.coarsen(s)
+	}
+
+	return value
+}
+
+func (r Rule) truncate(s string) string {
+	if ip := net.ParseIP(s); ip != nil {
+		if ip4 := ip.To4(); ip4 != nil {
+			return ip4.Mask(net.CIDRMask(orDefault(r.IPv4Prefix, defaultIPv4Prefix), 32)).String()
+		}
+		return ip.Mask(net.CIDRMask(orDefault(r.IPv6Prefix, defaultIPv6Prefix), 128)).String()
+	}
+
+	length := orDefault(r.Length, defaultTruncateChars)
+	if runes := []rune(s); len(runes) > length {
+		return string(runes[:length])
+	}
+
+	return s
+}
+
+// coarsen keeps the text before the first version in the value and the
+// leading components of that version, e.g. "Ubuntu 22.04.3 LTS" becomes
+// "Ubuntu 22.04".
+func (r Rule) coarsen(s string) string {
+	loc := versionPattern.FindStringIndex(s)
+	if loc == nil {
+		return s
+	}
+
+	parts := strings.Split(s[loc[0]:loc[1]], ".")
+	if keep := orDefault(r.Parts, defaultVersionParts); len(parts) > keep {
+		parts = parts[:keep]
+	}
+
+	return s[:loc[0]] + strings.Join(parts, ".")
+}
+
+func orDefault(value, defaultValue int) int {
+	if value == 0 {
+		return defaultValue
+	}
+	return value
+}
@@ -0,0 +1,97 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package privacy
+
+import (
+	"sort"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+)
+
+const (
+	// PresetFull reports all fields as collected.
+	PresetFull = "full"
+	// PresetStandard removes precise locations and network addresses, but
+	// keeps enough to tell regions and versions apart.
+	PresetStandard = "standard"
+	// PresetMinimal only reports coarse versions and counts.
+	PresetMinimal = "minimal"
+)
+
+var presets = map[string][]Rule{
+	PresetFull: nil,
+	PresetStandard: {
+		{Kind: agent.KindKubernetes, Field: "nodes.external_ip", Action: ActionTruncate},
+		{Kind: agent.KindKubermatic, Field: "seeds.location", Action: ActionDrop},
+		{Kind: agent.KindKubermatic, Field: "seeds.datacenters.location", Action: ActionDrop},
+	},
+	PresetMinimal: {
+		{Kind: agent.KindKubernetes, Field: "nodes.external_ip", Action: ActionDrop},
+		{Kind: agent.KindKubernetes, Field: "nodes.kernel_version", Action: ActionCoarsen},
+		{Kind: agent.KindKubernetes, Field: "nodes.os_image", Action: ActionCoarsen},
+		{Kind: agent.KindKubernetes, Field: "nodes.kubelet_version", Action: ActionCoarsen},
+		{Kind: agent.KindKubernetes, Field: "nodes.container_runtime_version", Action: ActionCoarsen},
+		{Kind: agent.KindKubernetes, Field: "kubernetes_version", Action: ActionCoarsen},
+		{Kind: agent.KindKubermatic, Field: "seeds.country", Action: ActionDrop},
+		{Kind: agent.KindKubermatic, Field: "seeds.location", Action: ActionDrop},
+		{Kind: agent.KindKubermatic, Field: "seeds.datacenters.country", Action: ActionDrop},
+		{Kind: agent.KindKubermatic, Field: "seeds.datacenters.location", Action: ActionDrop},
+		{Kind: agent.KindKubermatic, Field: "seeds.datacenters.region", Action: ActionDrop},
+		{Kind: agent.KindKubermatic, Field: "clusters.kubernetes_server_version", Action: ActionCoarsen},
+		{Kind: agent.KindKubermatic, Field: "clusters.cni_plugin.version", Action: ActionCoarsen},
+		{Kind: agent.KindKubermatic, Field: "external_clusters.kubernetes_version", Action: ActionCoarsen},
+		{Kind: agent.KindKubermatic, Field: "clusters.machine_deployments.kubelet_version", Action: ActionCoarsen},
+		{Kind: agent.KindKubermatic, Field: "clusters.applications.version", Action: ActionCoarsen},
+		{Kind: agent.KindKubermatic, Field: "application_adoption.version", Action: ActionCoarsen},
+	},
+}
+
+// Presets returns the names of all built-in policies.
+func Presets() []string {
+	names := make([]string, 0, len(presets))
+	for name := range presets {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+// ForPreset returns the built-in policy with the given name.
+func ForPreset(name string) (*Policy, error) {
+	return Load(name, "", "")
+}
+
+// presetOrder ranks the presets from most to least restrictive.
+var presetOrder = []string{PresetMinimal, PresetStandard, PresetFull}
+
+// IsPreset returns true if a built-in policy with the given name exists.
+func IsPreset(name string) bool {
+	_, ok := presets[name]
+	return ok
+}
+
+// MoreRestrictive returns the preset of both that reports less data.
+func MoreRestrictive(a, b string) string {
+	for _, name := range presetOrder {
+		if name == a || name == b {
+			return name
+		}
+	}
+
+	return a
+}
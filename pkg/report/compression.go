@@ -0,0 +1,90 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"compress/gzip"
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+const (
+	EncodingNone = ""
+	EncodingGzip = "gzip"
+	EncodingZstd = "zstd"
+)
+
+// Compress encodes the data with the given content coding.
+func Compress(data []byte, encoding string) ([]byte, error) {
+	var buf bytes.Buffer
+
+	var w io.WriteCloser
+	switch encoding {
+	case EncodingNone:
+		return data, nil
+	case EncodingGzip:
+		w = gzip.NewWriter(&buf)
+	case EncodingZstd:
+		zw, err := zstd.NewWriter(&buf)
+		if err != nil {
+			return nil, err
+		}
+		w = zw
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", encoding)
+	}
+
+	if _, err := w.Write(data); err != nil {
+		w.Close()
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// Decompress decodes data created by Compress.
+func Decompress(data []byte, encoding string) ([]byte, error) {
+	var r io.Reader
+	switch encoding {
+	case EncodingNone:
+		return data, nil
+	case EncodingGzip:
+		gr, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer gr.Close()
+		r = gr
+	case EncodingZstd:
+		zr, err := zstd.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, err
+		}
+		defer zr.Close()
+		r = zr
+	default:
+		return nil, fmt.Errorf("unsupported compression %q", encoding)
+	}
+
+	return io.ReadAll(r)
+}
@@ -0,0 +1,223 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"crypto/cipher"
+	"crypto/ecdh"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"golang.org/x/crypto/chacha20poly1305"
+	"golang.org/x/crypto/hkdf"
+)
+
+// AlgorithmX25519ChaCha20Poly1305 seals payloads with a key derived from an
+// ephemeral X25519 key exchange (HKDF-SHA256) using ChaCha20-Poly1305.
+const AlgorithmX25519ChaCha20Poly1305 = "X25519-HKDF-SHA256-ChaCha20Poly1305"
+
+const envelopeInfo = "telemetry-client envelope v1"
+
+// ErrNotSealed is returned when a payload is expected to be sealed but is not.
+var ErrNotSealed = errors.New("payload is not sealed")
+
+// SealedPayload is an encrypted payload that only the holder of the
+// recipient private key can open. It is valid JSON on its own, so it can be
+// stored and aggregated like any other record.
+type SealedPayload struct {
+	Algorithm string `json:"alg"`
+	// KeyID names the recipient key, so that collectors can rotate keys.
+	KeyID string `json:"kid,omitempty"`
+	// ClientUUID is copied from a sealed report, so that collectors can
+	// look up the signing key before opening the envelope. It is
+	// authenticated, but not encrypted.
+	ClientUUID string `json:"client_uuid,omitempty"`
+	// Encoding is the compression applied to the payload before it was
+	// sealed, it is undone by Open.
+	Encoding           string `json:"enc,omitempty"`
+	EphemeralPublicKey []byte `json:"epk"`
+	Nonce              []byte `json:"nonce"`
+	Ciphertext         []byte `json:"ciphertext"`
+}
+
+// Seal encrypts the payload for the given recipient, compressing it with
+// the given encoding first (disabled if empty). If the payload is a report,
+// its client UUID is kept in the envelope.
+func Seal(payload []byte, recipient *ecdh.PublicKey, keyID, encoding string) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().GenerateKey(rand.Reader)
+	if err != nil {
+		return nil, err
+	}
+
+	sharedSecret, err := ephemeral.ECDH(recipient)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed := &SealedPayload{
+		Algorithm:          AlgorithmX25519ChaCha20Poly1305,
+		KeyID:              keyID,
+		ClientUUID:         reportClientUUID(payload),
+		Encoding:           encoding,
+		EphemeralPublicKey: ephemeral.PublicKey().Bytes(),
+		Nonce:              make([]byte, chacha20poly1305.NonceSize),
+	}
+
+	aead, err := sealed.aead(sharedSecret, recipient.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := io.ReadFull(rand.Reader, sealed.Nonce); err != nil {
+		return nil, err
+	}
+
+	// ciphertext does not compress, so the plaintext is compressed instead
+	payload, err = Compress(payload, encoding)
+	if err != nil {
+		return nil, err
+	}
+
+	sealed.Ciphertext = aead.Seal(nil, sealed.Nonce, payload, sealed.additionalData())
+
+	return json.Marshal(sealed)
+}
+
+// IsSealed returns true if the data looks like a SealedPayload.
+func IsSealed(data []byte) bool {
+	_, err := ParseSealed(data)
+	return err == nil
+}
+
+// ParseSealed decodes a SealedPayload without opening it.
+func ParseSealed(data []byte) (*SealedPayload, error) {
+	sealed := &SealedPayload{}
+	if err := json.Unmarshal(data, sealed); err != nil {
+		return nil, ErrNotSealed
+	}
+	if sealed.Algorithm != AlgorithmX25519ChaCha20Poly1305 {
+		return nil, ErrNotSealed
+	}
+
+	return sealed, nil
+}
+
+// Open decrypts a payload created by Seal.
+func Open(data []byte, key *ecdh.PrivateKey) ([]byte, error) {
+	sealed, err := ParseSealed(data)
+	if err != nil {
+		return nil, err
+	}
+
+	return sealed.Open(key)
+}
+
+// Open decrypts the payload with the recipient's private key.
+func (s *SealedPayload) Open(key *ecdh.PrivateKey) ([]byte, error) {
+	ephemeral, err := ecdh.X25519().NewPublicKey(s.EphemeralPublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("invalid ephemeral key: %w", err)
+	}
+
+	sharedSecret, err := key.ECDH(ephemeral)
+	if err != nil {
+		return nil, err
+	}
+
+	aead, err := s.aead(sharedSecret, key.PublicKey().Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	if len(s.Nonce) != aead.NonceSize() {
+		return nil, errors.New("invalid nonce")
+	}
+
+	payload, err := aead.Open(nil, s.Nonce, s.Ciphertext, s.additionalData())
+	if err != nil {
+		return nil, fmt.Errorf("failed to decrypt payload: %w", err)
+	}
+
+	payload, err = Decompress(payload, s.Encoding)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress payload: %w", err)
+	}
+
+	return payload, nil
+}
+
+// aead derives the symmetric key, binding it to both public keys.
+func (s *SealedPayload) aead(sharedSecret, recipient []byte) (cipher.AEAD, error) {
+	salt := make([]byte, 0, len(s.EphemeralPublicKey)+len(recipient))
+	salt = append(salt, s.EphemeralPublicKey...)
+	salt = append(salt, recipient...)
+
+	key := make([]byte, chacha20poly1305.KeySize)
+	if _, err := io.ReadFull(hkdf.New(sha256.New, sharedSecret, salt, []byte(envelopeInfo)), key); err != nil {
+		return nil, err
+	}
+
+	return chacha20poly1305.New(key)
+}
+
+func (s *SealedPayload) additionalData() []byte {
+	// envelopes without a client UUID or encoding, like uncompressed
+	// sealed records, keep the original format
+	switch {
+	case s.Encoding != "":
+		return []byte(s.Algorithm + "/" + s.KeyID + "/" + s.ClientUUID + "/" + s.Encoding)
+	case s.ClientUUID != "":
+		return []byte(s.Algorithm + "/" + s.KeyID + "/" + s.ClientUUID)
+	default:
+		return []byte(s.Algorithm + "/" + s.KeyID)
+	}
+}
+
+// ParseX25519PublicKey parses a PEM encoded PKIX X25519 public key.
+func ParseX25519PublicKey(data []byte) (*ecdh.PublicKey, error) {
+	key, err := parsePEM(data, "PUBLIC KEY", x509.ParsePKIXPublicKey)
+	if err != nil {
+		return nil, err
+	}
+
+	publicKey, ok := key.(*ecdh.PublicKey)
+	if !ok || publicKey.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("expected an X25519 public key, got %T", key)
+	}
+
+	return publicKey, nil
+}
+
+// ParseX25519PrivateKey parses a PEM encoded PKCS #8 X25519 private key.
+func ParseX25519PrivateKey(data []byte) (*ecdh.PrivateKey, error) {
+	key, err := parsePEM(data, "PRIVATE KEY", x509.ParsePKCS8PrivateKey)
+	if err != nil {
+		return nil, err
+	}
+
+	privateKey, ok := key.(*ecdh.PrivateKey)
+	if !ok || privateKey.Curve() != ecdh.X25519() {
+		return nil, fmt.Errorf("expected an X25519 private key, got %T", key)
+	}
+
+	return privateKey, nil
+}
@@ -0,0 +1,291 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package report
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"crypto/hmac"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+const (
+	// SignatureHeader carries the detached signature of an upload
+	// in the form <algorithm>=<base64 signature>.
+	SignatureHeader = "X-Telemetry-Signature"
+	// KeyIDHeader names the key that was used to sign an upload.
+	KeyIDHeader = "X-Telemetry-Key-Id"
+
+	AlgorithmHMACSHA256 = "hmac-sha256"
+	AlgorithmEd25519    = "ed25519"
+)
+
+var (
+	// ErrUnsigned is returned when a signature is required but missing.
+	ErrUnsigned = errors.New("payload is not signed")
+	// ErrUnknownKey is returned when the key used for signing is unknown for the client.
+	ErrUnknownKey = errors.New("unknown signing key")
+	// ErrInvalidSignature is returned when the signature does not match the payload.
+	ErrInvalidSignature = errors.New("invalid signature")
+)
+
+// Signature is a detached signature of a payload.
+type Signature struct {
+	Algorithm string
+	KeyID     string
+	Value     []byte
+}
+
+// SetHeader adds the signature to the given HTTP headers.
+func (s Signature) SetHeader(h http.Header) {
+	h.Set(SignatureHeader, fmt.Sprintf("%s=%s", s.Algorithm, base64.StdEncoding.EncodeToString(s.Value)))
+	h.Set(KeyIDHeader, s.KeyID)
+}
+
+// SignatureFromHeader reads a signature previously added by SetHeader.
+func SignatureFromHeader(h http.Header) (Signature, error) {
+	value := h.Get(SignatureHeader)
+	if value == "" {
+		return Signature{}, ErrUnsigned
+	}
+
+	algorithm, encoded, ok := strings.Cut(value, "=")
+	if !ok {
+		return Signature{}, fmt.Errorf("malformed %s header", SignatureHeader)
+	}
+
+	sig, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return Signature{}, fmt.Errorf("malformed %s header: %w", SignatureHeader, err)
+	}
+
+	return Signature{
+		Algorithm: algorithm,
+		KeyID:     h.Get(KeyIDHeader),
+		Value:     sig,
+	}, nil
+}
+
+// Signer creates detached signatures for payloads.
+type Signer interface {
+	Sign(payload []byte) (Signature, error)
+}
+
+type hmacSigner struct {
+	keyID  string
+	secret []byte
+}
+
+// NewHMACSigner returns a Signer that uses HMAC-SHA256 with a shared secret.
+func NewHMACSigner(keyID string, secret []byte) Signer {
+	return hmacSigner{keyID: keyID, secret: secret}
+}
+
+func (s hmacSigner) Sign(payload []byte) (Signature, error) {
+	return Signature{
+		Algorithm: AlgorithmHMACSHA256,
+		KeyID:     s.keyID,
+		Value:     hmacSHA256(s.secret, payload),
+	}, nil
+}
+
+type ed25519Signer struct {
+	keyID string
+	key   ed25519.PrivateKey
+}
+
+// NewEd25519Signer returns a Signer that uses an Ed25519 private key.
+func NewEd25519Signer(keyID string, key ed25519.PrivateKey) Signer {
+	return ed25519Signer{keyID: keyID, key: key}
+}
+
+func (s ed25519Signer) Sign(payload []byte) (Signature, error) {
+	return Signature{
+		Algorithm: AlgorithmEd25519,
+		KeyID:     s.keyID,
+		Value:     ed25519.Sign(s.key, payload),
+	}, nil
+}
+
+// LoadSigner creates a Signer from a key file. For HMAC-SHA256 the file
+// contains the shared secret, for Ed25519 a PEM encoded PKCS #8 private key.
+func LoadSigner(algorithm, keyID, keyFile string) (Signer, error) {
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read signing key: %w", err)
+	}
+
+	switch algorithm {
+	case AlgorithmHMACSHA256:
+		secret := bytes.TrimSpace(data)
+		if len(secret) == 0 {
+			return nil, errors.New("signing secret is empty")
+		}
+		return NewHMACSigner(keyID, secret), nil
+
+	case AlgorithmEd25519:
+		key, err := parsePEM(data, "PRIVATE KEY", x509.ParsePKCS8PrivateKey)
+		if err != nil {
+			return nil, err
+		}
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("expected an Ed25519 private key, got %T", key)
+		}
+		return NewEd25519Signer(keyID, privateKey), nil
+
+	default:
+		return nil, fmt.Errorf("unsupported signing algorithm %q", algorithm)
+	}
+}
+
+// VerificationKey is the key a collector uses to verify signatures of a client.
+type VerificationKey struct {
+	Algorithm string
+	// Secret is the shared secret for HMAC-SHA256.
+	Secret []byte
+	// PublicKey is the public key for Ed25519.
+	PublicKey ed25519.PublicKey
+}
+
+// ParseEd25519PublicKey parses a PEM encoded PKIX Ed25519 public key.
+func ParseEd25519PublicKey(data []byte) (VerificationKey, error) {
+	key, err := parsePEM(data, "PUBLIC KEY", x509.ParsePKIXPublicKey)
+	if err != nil {
+		return VerificationKey{}, err
+	}
+
+	publicKey, ok := key.(ed25519.PublicKey)
+	if !ok {
+		return VerificationKey{}, fmt.Errorf("expected an Ed25519 public key, got %T", key)
+	}
+
+	return VerificationKey{Algorithm: AlgorithmEd25519, PublicKey: publicKey}, nil
+}
+
+// KeyStore looks up the verification keys of clients.
+type KeyStore interface {
+	// VerificationKey returns the key with the given ID that belongs to the
+	// client, or ErrUnknownKey.
+	VerificationKey(clientUUID, keyID string) (VerificationKey, error)
+}
+
+// KeyMap is a static KeyStore, indexed by client UUID and key ID.
+type KeyMap map[string]map[string]VerificationKey
+
+func (m KeyMap) VerificationKey(clientUUID, keyID string) (VerificationKey, error) {
+	key, ok := m[clientUUID][keyID]
+	if !ok {
+		return VerificationKey{}, ErrUnknownKey
+	}
+	return key, nil
+}
+
+// Verifier checks the signatures of uploaded reports.
+type Verifier struct {
+	keys KeyStore
+}
+
+func NewVerifier(keys KeyStore) *Verifier {
+	return &Verifier{keys: keys}
+}
+
+// Verify checks that the payload was signed by one of the keys of the client.
+func (v *Verifier) Verify(clientUUID string, payload []byte, sig Signature) error {
+	if len(sig.Value) == 0 {
+		return ErrUnsigned
+	}
+
+	key, err := v.keys.VerificationKey(clientUUID, sig.KeyID)
+	if err != nil {
+		return err
+	}
+
+	// the algorithm is dictated by the key, never by the sender
+	if key.Algorithm != sig.Algorithm {
+		return fmt.Errorf("%w: key %q is used with %s, not %s", ErrInvalidSignature, sig.KeyID, key.Algorithm, sig.Algorithm)
+	}
+
+	var valid bool
+	switch key.Algorithm {
+	case AlgorithmHMACSHA256:
+		valid = hmac.Equal(hmacSHA256(key.Secret, payload), sig.Value)
+	case AlgorithmEd25519:
+		valid = len(key.PublicKey) == ed25519.PublicKeySize && ed25519.Verify(key.PublicKey, payload, sig.Value)
+	default:
+		return fmt.Errorf("unsupported signing algorithm %q", key.Algorithm)
+	}
+
+	if !valid {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// VerifyReport checks the signature of an uploaded report, using the client
+// UUID contained in the report to look up the key. The signature covers the
+// uncompressed request body: either the report itself or, if it was
+// encrypted, the SealedPayload, which carries the client UUID of the sealed
+// report.
+func (v *Verifier) VerifyReport(payload []byte, sig Signature) error {
+	var r struct {
+		ClientUUID string `json:"client_uuid"`
+	}
+	if err := json.Unmarshal(payload, &r); err != nil {
+		return fmt.Errorf("failed to decode report: %w", err)
+	}
+
+	return v.Verify(r.ClientUUID, payload, sig)
+}
+
+// reportClientUUID returns the client UUID of a report, or an empty string
+// if the payload is not a report.
+func reportClientUUID(payload []byte) string {
+	var r struct {
+		ClientUUID string `json:"client_uuid"`
+	}
+	if err := json.Unmarshal(payload, &r); err != nil {
+		return ""
+	}
+
+	return r.ClientUUID
+}
+
+func hmacSHA256(secret, payload []byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
+
+func parsePEM(data []byte, blockType string, parse func([]byte) (any, error)) (any, error) {
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != blockType {
+		return nil, fmt.Errorf("no PEM block of type %q found", blockType)
+	}
+
+	return parse(block.Bytes)
+}
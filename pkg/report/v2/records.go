@@ -0,0 +1,83 @@
+/*
+Copyright 2026 The Telemetry Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v2
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/kubermatic/telemetry-client/pkg/agent"
+	kubermatictypes "github.com/kubermatic/telemetry-client/pkg/agent/kubermatic/v2/types"
+	kubernetestypes "github.com/kubermatic/telemetry-client/pkg/agent/kubernetes/v2/types"
+	telemetryversion "github.com/kubermatic/telemetry-client/pkg/version"
+)
+
+// Records are the decoded records of a report, grouped by kind.
+type Records struct {
+	Kubernetes []kubernetestypes.Record
+	Kubermatic []kubermatictypes.Record
+	// Skipped is the number of records that are not v2 kubernetes or
+	// kubermatic records, e.g. because they are encrypted.
+	Skipped int
+}
+
+// ParseReport decodes a report as created by the reporter.
+func ParseReport(data []byte) (*Report, error) {
+	r := &Report{}
+	if err := json.Unmarshal(data, r); err != nil {
+		return nil, fmt.Errorf("failed to decode report: %w", err)
+	}
+	if r.Version != telemetryversion.V2Version {
+		return nil, fmt.Errorf("unsupported report version %q", r.Version)
+	}
+
+	return r, nil
+}
+
+// DecodeRecords decodes all records of the report that it knows about.
+func (r *Report) DecodeRecords() (*Records, error) {
+	records := &Records{}
+
+	for i, raw := range r.Records {
+		var kv agent.KindVersion
+		if err := json.Unmarshal(raw, &kv); err != nil || kv.Version != telemetryversion.V2Version {
+			records.Skipped++
+			continue
+		}
+
+		switch kv.Kind {
+		case agent.KindKubernetes:
+			record := kubernetestypes.Record{}
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return nil, fmt.Errorf("failed to decode record %d: %w", i, err)
+			}
+			records.Kubernetes = append(records.Kubernetes, record)
+
+		case agent.KindKubermatic:
+			record := kubermatictypes.Record{}
+			if err := json.Unmarshal(raw, &record); err != nil {
+				return nil, fmt.Errorf("failed to decode record %d: %w", i, err)
+			}
+			records.Kubermatic = append(records.Kubermatic, record)
+
+		default:
+			records.Skipped++
+		}
+	}
+
+	return records, nil
+}
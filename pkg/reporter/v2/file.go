@@ -17,10 +17,14 @@ limitations under the License.
 package v2
 
 import (
+	"compress/gzip"
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/kubermatic/telemetry-client/pkg/datastore"
@@ -70,7 +74,7 @@ func (d fileReporter) Report(ctx context.Context) error {
 	}
 
 	for _, file := range files {
-		b, err := os.ReadFile(filepath.Join(d.path, file))
+		b, err := readRecord(filepath.Join(d.path, file))
 		if err != nil {
 			return err
 		}
@@ -85,3 +89,24 @@ func (d fileReporter) Report(ctx context.Context) error {
 
 	return d.dataStore.Store(ctx, data)
 }
+
+// readRecord reads a record file, transparently decompressing .gz files.
+func readRecord(filename string) ([]byte, error) {
+	if !strings.HasSuffix(filename, ".gz") {
+		return os.ReadFile(filename)
+	}
+
+	f, err := os.Open(filename)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	r, err := gzip.NewReader(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decompress %s: %w", filename, err)
+	}
+	defer r.Close()
+
+	return io.ReadAll(r)
+}